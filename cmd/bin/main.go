@@ -17,6 +17,8 @@ func main() {
 
 	serverCmd := flag.NewFlagSet("server", flag.ExitOnError)
 	seedCmd := flag.NewFlagSet("seed", flag.ExitOnError)
+	purgeCmd := flag.NewFlagSet("purge", flag.ExitOnError)
+	brandsCmd := flag.NewFlagSet("brands", flag.ExitOnError)
 	// wsCmd := flag.NewFlagSet("ws", flag.ExitOnError)
 
 	if len(os.Args) < 2 {
@@ -28,6 +30,10 @@ func main() {
 	switch os.Args[1] {
 	case "seed":
 		cmd.RunSeed(seedCmd, os.Args[2:])
+	case "purge":
+		cmd.RunPurge(purgeCmd, os.Args[2:])
+	case "brands":
+		cmd.RunBrands(brandsCmd, os.Args[2:])
 	case "server":
 		cmd.RunServer(serverCmd, os.Args[2:])
 	default:
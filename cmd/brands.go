@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/internal/module/shop/repository"
+	"codebase-app/internal/module/shop/service"
+	"context"
+	"flag"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunBrands links the products still carrying a free-text merk to brands,
+// spellings of the same brand end up on one brand.
+func RunBrands(cmd *flag.FlagSet, args []string) {
+	var (
+		dryRun = cmd.Bool("dry-run", false, "only report how the merk values would be grouped")
+		ctx    = context.Background()
+	)
+
+	if err := cmd.Parse(args); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	adapter.Adapters.Sync(
+		adapter.WithShopeefunPostgres(),
+	)
+	defer func() {
+		if err := adapter.Adapters.Unsync(); err != nil {
+			log.Fatal().Err(err).Msg("Error while closing database connection")
+		}
+	}()
+
+	var (
+		repo = repository.NewShopRepository(adapter.Adapters.ShopeefunPostgres)
+		svc  = service.NewShopService(repo)
+	)
+
+	resp, err := svc.NormalizeBrands(ctx, &entity.NormalizeBrandsRequest{DryRun: *dryRun})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error while normalizing brands")
+	}
+
+	for _, g := range resp.Groups {
+		log.Info().
+			Str("brand", g.Name).
+			Strs("merks", g.Merks).
+			Int("products", g.Products).
+			Msg("Brand group")
+	}
+
+	log.Info().
+		Bool("dry_run", *dryRun).
+		Int("brands", len(resp.Groups)).
+		Int("products", resp.Products).
+		Msg("Brands normalized")
+}
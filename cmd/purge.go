@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/middleware"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/internal/module/shop/repository"
+	"codebase-app/internal/module/shop/service"
+	"context"
+	"flag"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunPurge permanently removes the shops, products and categories that have
+// been in the trash longer than the retention, together with their images.
+func RunPurge(cmd *flag.FlagSet, args []string) {
+	var (
+		retention = cmd.Int("retention", config.Envs.App.TrashRetentionDays, "days a deleted row is kept before it is purged")
+		ctx       = context.Background()
+	)
+
+	if err := cmd.Parse(args); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	if *retention < 1 {
+		log.Fatal().Int("retention", *retention).Msg("Retention must be at least 1 day")
+	}
+
+	adapter.Adapters.Sync(
+		adapter.WithShopeefunPostgres(),
+	)
+	defer func() {
+		if err := adapter.Adapters.Unsync(); err != nil {
+			log.Fatal().Err(err).Msg("Error while closing database connection")
+		}
+	}()
+
+	var (
+		repo   = repository.NewShopRepository(adapter.Adapters.ShopeefunPostgres)
+		svc    = service.NewShopService(repo)
+		before = time.Now().AddDate(0, 0, -*retention)
+	)
+
+	resp, err := svc.PurgeTrash(ctx, &entity.PurgeTrashRequest{Before: before})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error while purging trash")
+	}
+
+	// the rows are gone already, a failed image removal is only logged
+	var failed int
+	for _, url := range resp.ImageURLs {
+		if err := middleware.DeleteUploadedImage(ctx, url); err != nil {
+			log.Warn().Err(err).Str("url", url).Msg("Error while deleting image")
+			failed++
+		}
+	}
+
+	log.Info().
+		Time("before", before).
+		Int("shops", resp.Shops).
+		Int("products", resp.Products).
+		Int("categories", resp.Categories).
+		Strs("kept_components", resp.KeptComponentIds).
+		Int("images", len(resp.ImageURLs)-failed).
+		Int("images_failed", failed).
+		Msg("Trash purged")
+}
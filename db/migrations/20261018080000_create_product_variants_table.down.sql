@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS product_variant_options;
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS product_options;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS product_images (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS product_images_product_id_idx ON product_images (product_id, position);
+CREATE UNIQUE INDEX IF NOT EXISTS product_images_primary_key ON product_images (product_id) WHERE is_primary;
+
+-- move the existing single image into the gallery as the primary image
+INSERT INTO product_images (product_id, url, position, is_primary)
+SELECT id, image_url, 0, true
+FROM products
+WHERE image_url IS NOT NULL AND image_url <> '';
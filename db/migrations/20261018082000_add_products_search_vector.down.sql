@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS categories_search_vector_trigger ON categories;
+DROP FUNCTION IF EXISTS categories_search_vector_update();
+DROP TRIGGER IF EXISTS products_search_vector_trigger ON products;
+DROP FUNCTION IF EXISTS products_search_vector_update();
+DROP INDEX IF EXISTS products_search_vector_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
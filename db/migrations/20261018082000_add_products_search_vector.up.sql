@@ -0,0 +1,36 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+-- name weighs most, then merk, category and description
+CREATE OR REPLACE FUNCTION products_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', COALESCE(NEW.name, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.merk, '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE((SELECT name FROM categories WHERE id = NEW.category_id), '')), 'C') ||
+        setweight(to_tsvector('simple', COALESCE(NEW.description, '')), 'D');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS products_search_vector_trigger ON products;
+CREATE TRIGGER products_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF name, merk, description, category_id ON products
+    FOR EACH ROW EXECUTE FUNCTION products_search_vector_update();
+
+-- renaming a category has to refresh the vectors of its products
+CREATE OR REPLACE FUNCTION categories_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    UPDATE products SET category_id = category_id WHERE category_id = NEW.id;
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS categories_search_vector_trigger ON categories;
+CREATE TRIGGER categories_search_vector_trigger
+    AFTER UPDATE OF name ON categories
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION categories_search_vector_update();
+
+UPDATE products SET name = name;
+
+CREATE INDEX IF NOT EXISTS products_search_vector_idx ON products USING GIN (search_vector);
@@ -0,0 +1,3 @@
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES categories(id);
+
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories (parent_id);
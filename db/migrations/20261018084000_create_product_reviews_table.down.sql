@@ -0,0 +1,5 @@
+ALTER TABLE products DROP COLUMN IF EXISTS review_count;
+ALTER TABLE products ALTER COLUMN rating DROP DEFAULT;
+ALTER TABLE products ALTER COLUMN rating TYPE INTEGER USING ROUND(rating);
+
+DROP TABLE IF EXISTS product_reviews;
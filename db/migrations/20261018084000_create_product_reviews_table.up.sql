@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS product_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body TEXT NOT NULL DEFAULT '',
+    photo_urls TEXT[] NOT NULL DEFAULT '{}',
+    seller_reply TEXT,
+    replied_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS product_reviews_product_id_user_id_key ON product_reviews (product_id, user_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS product_reviews_product_id_idx ON product_reviews (product_id, created_at);
+
+-- the rating is now the average of the reviews, seller supplied values are dropped
+-- (converting them as is would overflow NUMERIC(3, 2) from 10 up)
+ALTER TABLE products ALTER COLUMN rating TYPE NUMERIC(3, 2) USING 0;
+ALTER TABLE products ALTER COLUMN rating SET DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS review_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS categories_trash_idx;
+DROP INDEX IF EXISTS products_trash_idx;
+DROP INDEX IF EXISTS shops_trash_idx;
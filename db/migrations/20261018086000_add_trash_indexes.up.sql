@@ -0,0 +1,4 @@
+-- the trash listings and the purge command only look at soft deleted rows
+CREATE INDEX IF NOT EXISTS shops_trash_idx ON shops (user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS products_trash_idx ON products (user_id, deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS categories_trash_idx ON categories (user_id, deleted_at) WHERE deleted_at IS NOT NULL;
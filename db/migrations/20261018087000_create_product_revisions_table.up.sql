@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS product_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    before JSONB,
+    after JSONB NOT NULL,
+    rollback_of UUID REFERENCES product_revisions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS product_revisions_product_id_idx ON product_revisions (product_id, created_at DESC);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS product_slug_redirects;
+DROP TABLE IF EXISTS shop_slug_redirects;
+DROP INDEX IF EXISTS products_shop_id_slug_idx;
+DROP INDEX IF EXISTS shops_slug_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS slug;
+ALTER TABLE shops DROP COLUMN IF EXISTS slug;
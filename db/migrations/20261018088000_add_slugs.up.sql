@@ -0,0 +1,48 @@
+ALTER TABLE shops ADD COLUMN IF NOT EXISTS slug VARCHAR(120);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS slug VARCHAR(120);
+
+-- existing rows get a slug from their name, a clash keeps the first one and
+-- suffixes the others with the start of their id
+UPDATE shops s SET slug = n.base || CASE WHEN n.rn > 1 THEN '-' || LEFT(s.id::text, 8) ELSE '' END
+FROM (
+    SELECT id, base, ROW_NUMBER() OVER (PARTITION BY base ORDER BY created_at, id) as rn
+    FROM (
+        SELECT id, created_at,
+            COALESCE(NULLIF(TRIM(BOTH '-' FROM LEFT(regexp_replace(LOWER(name), '[^a-z0-9]+', '-', 'g'), 100)), ''), 'toko') as base
+        FROM shops
+    ) b
+) n
+WHERE s.id = n.id;
+
+UPDATE products p SET slug = n.base || CASE WHEN n.rn > 1 THEN '-' || LEFT(p.id::text, 8) ELSE '' END
+FROM (
+    SELECT id, base, ROW_NUMBER() OVER (PARTITION BY shop_id, base ORDER BY created_at, id) as rn
+    FROM (
+        SELECT id, shop_id, created_at,
+            COALESCE(NULLIF(TRIM(BOTH '-' FROM LEFT(regexp_replace(LOWER(name), '[^a-z0-9]+', '-', 'g'), 100)), ''), 'produk') as base
+        FROM products
+    ) b
+) n
+WHERE p.id = n.id;
+
+ALTER TABLE shops ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE products ALTER COLUMN slug SET NOT NULL;
+
+-- deleted rows keep their slug so a restore never clashes
+CREATE UNIQUE INDEX IF NOT EXISTS shops_slug_idx ON shops (slug);
+CREATE UNIQUE INDEX IF NOT EXISTS products_shop_id_slug_idx ON products (shop_id, slug);
+
+-- the slugs a shop or a product had before a rename, so old links keep working
+CREATE TABLE IF NOT EXISTS shop_slug_redirects (
+    old_slug VARCHAR(120) PRIMARY KEY,
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS product_slug_redirects (
+    shop_id UUID NOT NULL,
+    old_slug VARCHAR(120) NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (shop_id, old_slug)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_attributes;
+DROP TABLE IF EXISTS category_attributes;
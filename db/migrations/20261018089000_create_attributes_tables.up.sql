@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS category_attributes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('text', 'number', 'boolean', 'enum')),
+    allowed_values TEXT[] NOT NULL DEFAULT '{}',
+    unit VARCHAR(20) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    UNIQUE (category_id, code)
+);
+
+CREATE TABLE IF NOT EXISTS product_attributes (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    attribute_id UUID NOT NULL REFERENCES category_attributes(id) ON DELETE CASCADE,
+    value VARCHAR(255) NOT NULL,
+    PRIMARY KEY (product_id, attribute_id)
+);
+
+-- the attr[...] listing filter compares values case insensitively
+CREATE INDEX IF NOT EXISTS product_attributes_value_idx ON product_attributes (attribute_id, LOWER(value));
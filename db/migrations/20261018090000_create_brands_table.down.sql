@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS products_brand_id_idx;
+ALTER TABLE products DROP COLUMN IF EXISTS brand_id;
+DROP TABLE IF EXISTS brand_aliases;
+DROP TABLE IF EXISTS brands;
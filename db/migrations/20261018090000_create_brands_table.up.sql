@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS brands (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    logo_url TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS brands_name_idx ON brands (LOWER(name));
+
+-- every spelling a brand is known by, keyed by its normalized form, the
+-- canonical name of the brand is one of them
+CREATE TABLE IF NOT EXISTS brand_aliases (
+    alias VARCHAR(255) PRIMARY KEY,
+    brand_id UUID NOT NULL REFERENCES brands(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS brand_aliases_brand_id_idx ON brand_aliases (brand_id);
+
+-- merk stays as the display name, the existing values are linked by the
+-- brands command
+ALTER TABLE products ADD COLUMN IF NOT EXISTS brand_id UUID REFERENCES brands(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS products_brand_id_idx ON products (brand_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS product_bundle_items;
+ALTER TABLE products DROP COLUMN IF EXISTS type;
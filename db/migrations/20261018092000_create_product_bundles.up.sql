@@ -0,0 +1,14 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS type VARCHAR(10) NOT NULL DEFAULT 'single'
+    CHECK (type IN ('single', 'bundle'));
+
+-- a bundle has no stock of its own, it is derived from the components
+CREATE TABLE IF NOT EXISTS product_bundle_items (
+    bundle_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    position INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (bundle_id, product_id),
+    CHECK (bundle_id <> product_id)
+);
+
+CREATE INDEX IF NOT EXISTS product_bundle_items_product_id_idx ON product_bundle_items (product_id);
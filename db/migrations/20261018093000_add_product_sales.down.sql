@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS product_price_history;
+ALTER TABLE products
+    DROP COLUMN IF EXISTS sale_ends_at,
+    DROP COLUMN IF EXISTS sale_starts_at,
+    DROP COLUMN IF EXISTS sale_price;
@@ -0,0 +1,23 @@
+-- the sale price replaces the price between sale_starts_at and sale_ends_at,
+-- an open bound means the sale has already started or never ends
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS sale_price DECIMAL(10, 2),
+    ADD COLUMN IF NOT EXISTS sale_starts_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS sale_ends_at TIMESTAMP WITH TIME ZONE;
+
+-- every change of the price or the sale of a product, newest last
+CREATE TABLE IF NOT EXISTS product_price_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    user_id UUID,
+    price DECIMAL(10, 2) NOT NULL,
+    sale_price DECIMAL(10, 2),
+    sale_starts_at TIMESTAMP WITH TIME ZONE,
+    sale_ends_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS product_price_history_product_id_idx ON product_price_history (product_id, created_at);
+
+INSERT INTO product_price_history (product_id, user_id, price, created_at)
+SELECT id, user_id, price, created_at FROM products;
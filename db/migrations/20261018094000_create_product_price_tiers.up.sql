@@ -0,0 +1,7 @@
+-- harga grosir: buying at least min_quantity units costs price per unit
+CREATE TABLE IF NOT EXISTS product_price_tiers (
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    min_quantity INT NOT NULL CHECK (min_quantity > 1),
+    price DECIMAL(10, 2) NOT NULL CHECK (price > 0),
+    PRIMARY KEY (product_id, min_quantity)
+);
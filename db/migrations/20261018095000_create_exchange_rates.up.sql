@@ -0,0 +1,10 @@
+-- prices are stored in the currency of the shop, IDR unless chosen otherwise
+ALTER TABLE shops ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'IDR';
+
+-- rate is the value of one unit of the currency in IDR, used to show
+-- indicative prices to buyers abroad
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    currency CHAR(3) PRIMARY KEY,
+    rate DECIMAL(20, 8) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
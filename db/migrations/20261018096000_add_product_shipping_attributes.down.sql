@@ -0,0 +1,5 @@
+ALTER TABLE products
+    DROP COLUMN IF EXISTS height,
+    DROP COLUMN IF EXISTS width,
+    DROP COLUMN IF EXISTS length,
+    DROP COLUMN IF EXISTS weight;
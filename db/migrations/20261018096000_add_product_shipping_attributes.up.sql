@@ -0,0 +1,7 @@
+-- shipping attributes: weight in grams, dimensions in centimeters, left empty
+-- for the existing products until the seller fills them in
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS weight INT CHECK (weight > 0),
+    ADD COLUMN IF NOT EXISTS length INT CHECK (length > 0),
+    ADD COLUMN IF NOT EXISTS width INT CHECK (width > 0),
+    ADD COLUMN IF NOT EXISTS height INT CHECK (height > 0);
@@ -0,0 +1,3 @@
+ALTER TABLE product_bundle_items DROP CONSTRAINT IF EXISTS product_bundle_items_product_id_fkey;
+ALTER TABLE product_bundle_items ADD CONSTRAINT product_bundle_items_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+-- a component can't be purged while a bundle uses it, the bundle would become
+-- sellable again with the component missing
+ALTER TABLE product_bundle_items DROP CONSTRAINT IF EXISTS product_bundle_items_product_id_fkey;
+ALTER TABLE product_bundle_items ADD CONSTRAINT product_bundle_items_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE RESTRICT;
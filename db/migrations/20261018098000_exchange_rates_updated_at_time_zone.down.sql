@@ -0,0 +1 @@
+ALTER TABLE exchange_rates ALTER COLUMN updated_at TYPE TIMESTAMP;
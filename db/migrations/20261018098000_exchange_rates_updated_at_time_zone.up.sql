@@ -0,0 +1,3 @@
+-- the stored times were written by NOW() in the session time zone, which is
+-- the zone the conversion reads them in
+ALTER TABLE exchange_rates ALTER COLUMN updated_at TYPE TIMESTAMP WITH TIME ZONE;
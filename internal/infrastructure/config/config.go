@@ -24,6 +24,7 @@ type Config struct {
 		LogFileWs               string `env:"APP_LOG_FILE_WS" env-default:"./logs/ws.log"`
 		LocalStoragePublicPath  string `env:"LOCAL_STORAGE_PUBLIC_PATH" env-default:"./storage/public"`
 		LocalStoragePrivatePath string `env:"LOCAL_STORAGE_PRIVATE_PATH" env-default:"./storage/private"`
+		TrashRetentionDays      int    `env:"TRASH_RETENTION_DAYS" env-default:"30" env-description:"days a deleted row stays in the trash before purge"`
 	}
 	DB struct {
 		ConnectionTimeout int `env:"DB_CONN_TIMEOUT" env-default:"30" env-description:"database timeout in seconds"`
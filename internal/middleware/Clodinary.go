@@ -1,8 +1,13 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
 
 	"github.com/cloudinary/cloudinary-go"
 	"github.com/cloudinary/cloudinary-go/api/uploader"
@@ -46,3 +51,74 @@ func UploadImageMiddleware(c *fiber.Ctx) error {
 	c.Locals("imageURL", uploadResp.SecureURL)
 	return c.Next()
 }
+
+func UploadImagesMiddleware(c *fiber.Ctx) error {
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return c.Next()
+	}
+
+	files := form.File["images"]
+	if len(files) == 0 {
+		return c.Next()
+	}
+
+	imageURLs := make([]string, 0, len(files))
+	for _, file := range files {
+		fileContent, err := file.Open()
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Gagal Untuk Mengupload Gambar",
+			})
+		}
+
+		uploadResp, err := cloudinaryClient.Upload.Upload(c.Context(), fileContent, uploader.UploadParams{
+			Folder: "product_images",
+		})
+		fileContent.Close()
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Gagal Untuk Mengupload Gambar",
+			})
+		}
+
+		imageURLs = append(imageURLs, uploadResp.SecureURL)
+	}
+
+	c.Locals("imageURLs", imageURLs)
+	return c.Next()
+}
+
+// DeleteUploadedImage removes an image uploaded by the middlewares above, the
+// public id is read back from its secure url.
+func DeleteUploadedImage(ctx context.Context, imageURL string) error {
+	publicId, err := cloudinaryPublicId(imageURL)
+	if err != nil {
+		return err
+	}
+
+	_, err = cloudinaryClient.Upload.Destroy(ctx, uploader.DestroyParams{PublicID: publicId})
+	return err
+}
+
+// cloudinaryPublicId turns ".../image/upload/v1712345678/product_images/abc.jpg"
+// into "product_images/abc".
+func cloudinaryPublicId(imageURL string) (string, error) {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return "", err
+	}
+
+	_, rest, ok := strings.Cut(u.Path, "/upload/")
+	if !ok {
+		return "", fmt.Errorf("not a cloudinary upload url: %s", imageURL)
+	}
+
+	// drop the optional version segment
+	if first, after, ok := strings.Cut(rest, "/"); ok && len(first) > 1 && first[0] == 'v' && strings.Trim(first[1:], "0123456789") == "" {
+		rest = after
+	}
+
+	return strings.TrimSuffix(rest, path.Ext(rest)), nil
+}
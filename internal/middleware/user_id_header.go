@@ -21,3 +21,13 @@ func UserIdHeader(c *fiber.Ctx) error {
 
 	return c.Next()
 }
+
+// OptionalUserIdHeader sets the user id when the header is present and lets
+// anonymous requests through.
+func OptionalUserIdHeader(c *fiber.Ctx) error {
+	if userId := c.Get("X-USER-ID"); userId != "" {
+		c.Locals("user_id", userId)
+	}
+
+	return c.Next()
+}
@@ -0,0 +1,147 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	AttributeTypeText    = "text"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeEnum    = "enum"
+)
+
+// CategoryAttribute defines a specification of the products in a category.
+// A category also carries the attributes of its ancestors, a definition with
+// the same code lower in the tree takes precedence.
+type CategoryAttribute struct {
+	Id            string   `json:"id" db:"id"`
+	CategoryId    string   `json:"categoryId" db:"category_id"`
+	Code          string   `json:"code" db:"code"`
+	Name          string   `json:"name" db:"name"`
+	Type          string   `json:"type" db:"type"`
+	AllowedValues []string `json:"allowedValues"`
+	Unit          string   `json:"unit" db:"unit"`
+}
+
+type GetCategoryAttributesRequest struct {
+	UserId     string `prop:"user_id" validate:"uuid" db:"user_id"`
+	CategoryId string `params:"id" validate:"uuid" db:"category_id"`
+}
+
+type GetCategoryAttributesResponse struct {
+	Attributes []CategoryAttribute `json:"attributes"`
+}
+
+type CreateCategoryAttributeRequest struct {
+	UserId     string `prop:"user_id" validate:"uuid" db:"user_id"`
+	CategoryId string `params:"id" validate:"uuid" db:"category_id"`
+
+	// Code is the key used by the attr[...] filter, it defaults to the slug of
+	// the name and cannot be changed afterwards.
+	Code          string   `json:"code" validate:"max=50" db:"code"`
+	Name          string   `json:"name" validate:"required,max=100" db:"name"`
+	Type          string   `json:"type" validate:"required,oneof=text number boolean enum" db:"type"`
+	AllowedValues []string `json:"allowedValues" validate:"required_if=Type enum,excluded_unless=Type enum,max=100,unique_in_slice,dive,required,max=100"`
+	Unit          string   `json:"unit" validate:"max=20" db:"unit"`
+}
+
+type CreateCategoryAttributeResponse struct {
+	CategoryAttribute
+}
+
+// UpdateCategoryAttributeRequest leaves the code and the type alone, values
+// stored for the products would no longer match them.
+type UpdateCategoryAttributeRequest struct {
+	UserId     string `prop:"user_id" validate:"uuid" db:"user_id"`
+	CategoryId string `params:"id" validate:"uuid" db:"category_id"`
+	Id         string `params:"attribute_id" validate:"uuid" db:"id"`
+
+	Name          string   `json:"name" validate:"required,max=100" db:"name"`
+	AllowedValues []string `json:"allowedValues" validate:"max=100,unique_in_slice,dive,required,max=100"`
+	Unit          string   `json:"unit" validate:"max=20" db:"unit"`
+}
+
+type UpdateCategoryAttributeResponse struct {
+	CategoryAttribute
+}
+
+type DeleteCategoryAttributeRequest struct {
+	UserId     string `prop:"user_id" validate:"uuid" db:"user_id"`
+	CategoryId string `params:"id" validate:"uuid" db:"category_id"`
+	Id         string `params:"attribute_id" validate:"uuid" db:"id"`
+}
+
+// ProductAttribute is the value of an attribute of a product.
+type ProductAttribute struct {
+	AttributeId string `json:"-" db:"attribute_id"`
+	Code        string `json:"code" db:"code"`
+	Name        string `json:"name" db:"name"`
+	Value       string `json:"value" db:"value"`
+	Unit        string `json:"unit" db:"unit"`
+}
+
+// SetProductAttributesRequest replaces all attribute values of the product,
+// Attributes is keyed by the attribute code.
+type SetProductAttributesRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+
+	Attributes map[string]string `json:"attributes" validate:"max=100,dive,keys,required,max=50,endkeys,max=255"`
+}
+
+type SetProductAttributesResponse struct {
+	Attributes []ProductAttribute `json:"attributes"`
+}
+
+// NormalizeAttributeNumber returns the stored form of a number value, a
+// decimal comma is accepted.
+func NormalizeAttributeNumber(raw string) (string, bool) {
+	f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(raw), ",", "."), 64)
+	if err != nil {
+		return "", false
+	}
+
+	return strconv.FormatFloat(f, 'f', -1, 64), true
+}
+
+// NormalizeAttributeBoolean returns the stored form of a boolean value.
+func NormalizeAttributeBoolean(raw string) (string, bool) {
+	b, err := strconv.ParseBool(strings.ToLower(strings.TrimSpace(raw)))
+	if err != nil {
+		return "", false
+	}
+
+	return strconv.FormatBool(b), true
+}
+
+// AttributeFilterValue is a value of an attr[code] filter in the stored form
+// of every attribute type, the type of the code is only known per category.
+type AttributeFilterValue struct {
+	// Text matches text and enum values regardless of case.
+	Text string
+
+	// Number and Unit split a value like "8GB", Number is empty when the
+	// value is not a number and Unit when none is given.
+	Number string
+	Unit   string
+
+	Boolean string
+}
+
+func NewAttributeFilterValue(raw string) AttributeFilterValue {
+	var (
+		value  = strings.TrimSpace(raw)
+		number = strings.TrimRightFunc(value, unicode.IsLetter)
+		v      = AttributeFilterValue{Text: strings.ToLower(value)}
+	)
+
+	if n, ok := NormalizeAttributeNumber(number); ok {
+		v.Number, v.Unit = n, value[len(number):]
+	}
+	v.Boolean, _ = NormalizeAttributeBoolean(value)
+
+	return v
+}
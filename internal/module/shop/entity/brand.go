@@ -0,0 +1,147 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"strings"
+)
+
+// brandSuffixes are the trailing words sellers add to a brand name that do
+// not make it another brand, e.g. "SAMSUNG Official Store".
+var brandSuffixes = [][]string{
+	{"official", "store"},
+	{"official", "shop"},
+	{"official"},
+}
+
+// BrandName cleans up a merk for display: surrounding and repeated spaces and
+// the brandSuffixes are removed, the casing is kept.
+func BrandName(merk string) string {
+	words := strings.Fields(merk)
+
+	for _, suffix := range brandSuffixes {
+		n := len(words) - len(suffix)
+		if n < 1 {
+			continue
+		}
+
+		match := true
+		for i, w := range suffix {
+			if !strings.EqualFold(words[n+i], w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			words = words[:n]
+			break
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// BrandKey is the form two spellings of the same brand share, it is used as
+// the alias of a brand. It returns an empty string for an empty merk.
+func BrandKey(merk string) string {
+	var sb strings.Builder
+
+	for _, c := range strings.ToLower(BrandName(merk)) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c > 127:
+			sb.WriteRune(c)
+		default:
+			sb.WriteByte(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+type BrandItem struct {
+	Id           string  `json:"id" db:"id"`
+	Name         string  `json:"name" db:"name"`
+	LogoURL      *string `json:"logoUrl" db:"logo_url"`
+	ProductCount int     `json:"productCount" db:"product_count"`
+}
+
+// BrandDetail is a brand with every spelling it is known by, the aliases are
+// in their BrandKey form.
+type BrandDetail struct {
+	Id      string   `json:"id" db:"id"`
+	Name    string   `json:"name" db:"name"`
+	LogoURL *string  `json:"logoUrl" db:"logo_url"`
+	Aliases []string `json:"aliases"`
+}
+
+type GetBrandRequest struct {
+	Id string `params:"id" validate:"uuid"`
+}
+
+// UpdateBrandRequest sets the canonical name and the logo of a brand, an empty
+// LogoURL removes the logo. The products of the brand take the new name.
+type UpdateBrandRequest struct {
+	UserId  string `prop:"user_id" validate:"uuid"`
+	Id      string `params:"id" validate:"uuid"`
+	Name    string `json:"name" validate:"required,max=255"`
+	LogoURL string `json:"logoUrl" validate:"omitempty,url,max=2048"`
+}
+
+// AddBrandAliasRequest makes Alias resolve to the brand. When it is the name of
+// another brand, that brand is merged into this one.
+type AddBrandAliasRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+	Id     string `params:"id" validate:"uuid"`
+	Alias  string `json:"alias" validate:"required,max=255"`
+}
+
+// DeleteBrandAliasRequest removes a spelling, the products already linked to
+// the brand keep it. The alias of the brand name cannot be removed.
+type DeleteBrandAliasRequest struct {
+	Id    string `params:"id" validate:"uuid"`
+	Alias string `params:"alias" validate:"required,max=255"`
+}
+
+type GetBrandsRequest struct {
+	Page     int    `query:"page" validate:"required"`
+	Paginate int    `query:"paginate" validate:"required"`
+	Keyword  string `query:"keyword" validate:"max=100"`
+}
+
+func (r *GetBrandsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type GetBrandsResponse struct {
+	Items []BrandItem `json:"items"`
+	Meta  types.Meta  `json:"meta"`
+}
+
+// MerkCount is a distinct merk of the products not linked to a brand yet.
+type MerkCount struct {
+	Merk     string `db:"merk"`
+	Products int    `db:"products"`
+}
+
+// BrandGroup is the set of merk spellings that become one brand.
+type BrandGroup struct {
+	Key      string   `json:"key"`
+	Name     string   `json:"name"`
+	Merks    []string `json:"merks"`
+	Products int      `json:"products"`
+}
+
+type NormalizeBrandsRequest struct {
+	// DryRun only reports the groups, nothing is written.
+	DryRun bool
+}
+
+type NormalizeBrandsResponse struct {
+	Groups   []BrandGroup `json:"groups"`
+	Products int          `json:"products"`
+}
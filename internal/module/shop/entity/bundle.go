@@ -0,0 +1,40 @@
+package entity
+
+import "codebase-app/pkg/types"
+
+const (
+	ProductTypeSingle = "single"
+	ProductTypeBundle = "bundle"
+)
+
+// BundleComponentRequest is a product put in a bundle, Quantity units of it
+// go in every bundle sold.
+type BundleComponentRequest struct {
+	ProductId string `json:"productId" form:"productId" validate:"required,uuid"`
+	Quantity  int    `json:"quantity" form:"quantity" validate:"required,min=1,max=100"`
+}
+
+type BundleComponent struct {
+	ProductId string      `json:"productId" db:"product_id"`
+	Slug      string      `json:"slug" db:"slug"`
+	Name      string      `json:"name" db:"name"`
+	Price     types.Money `json:"price" db:"price"`
+	ImageURL  string      `json:"imageUrl" db:"image_url"`
+	Quantity  int         `json:"quantity" db:"quantity"`
+
+	// Stock is what the component has available, 0 once it is deleted or
+	// unpublished.
+	Stock int `json:"stock" db:"stock"`
+}
+
+type SetBundleComponentsRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+
+	Components []BundleComponentRequest `json:"components" validate:"required,min=1,max=10,unique=ProductId,dive"`
+}
+
+type SetBundleComponentsResponse struct {
+	Components []BundleComponent `json:"components"`
+	TotalStock int               `json:"totalStock"`
+}
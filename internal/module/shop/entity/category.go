@@ -0,0 +1,34 @@
+package entity
+
+type CategoryNode struct {
+	Id       string         `json:"id" db:"id"`
+	Name     string         `json:"name" db:"name"`
+	ParentId *string        `json:"parentId" db:"parent_id"`
+	Children []CategoryNode `json:"children"`
+}
+
+type GetCategoryTreeRequest struct {
+	UserId string `prop:"user_id" validate:"required,uuid"`
+}
+
+type GetCategoryTreeResponse struct {
+	Categories []CategoryNode `json:"categories"`
+}
+
+type MoveCategoryRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+	Id     string `params:"id" validate:"uuid" db:"id"`
+
+	// ParentId is the new parent, empty moves the category to the root.
+	ParentId string `json:"parentId" validate:"omitempty,uuid" db:"parent_id"`
+}
+
+type MoveCategoryResponse struct {
+	Id       string  `json:"id" db:"id"`
+	ParentId *string `json:"parentId" db:"parent_id"`
+}
+
+type CategoryBreadcrumb struct {
+	Id   string `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+}
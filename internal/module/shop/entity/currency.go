@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
+
+// ExchangeRate is the value of one unit of Currency in the default currency.
+type ExchangeRate struct {
+	Currency  string    `json:"currency" db:"currency"`
+	Rate      float64   `json:"rate" db:"rate"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+type ExchangeRateRequest struct {
+	Currency string  `json:"currency" validate:"required,iso4217"`
+	Rate     float64 `json:"rate" validate:"required,gt=0"`
+}
+
+type SetExchangeRatesRequest struct {
+	// Rates are upserted, currencies left out keep their rate.
+	Rates []ExchangeRateRequest `json:"rates" validate:"required,min=1,max=200,unique=Currency,dive"`
+}
+
+type GetExchangeRatesResponse struct {
+	Base  string         `json:"base"`
+	Rates []ExchangeRate `json:"rates"`
+}
+
+// ConvertedPrice shows the prices of a product in the currency the buyer asked
+// for. The amounts are indicative, the product is paid in the shop currency.
+type ConvertedPrice struct {
+	Currency       string      `json:"currency"`
+	Rate           float64     `json:"rate"`
+	Indicative     bool        `json:"indicative"`
+	RateUpdatedAt  time.Time   `json:"rateUpdatedAt"`
+	Price          types.Money `json:"price"`
+	EffectivePrice types.Money `json:"effectivePrice"`
+	MinPrice       types.Money `json:"minPrice"`
+	MaxPrice       types.Money `json:"maxPrice"`
+}
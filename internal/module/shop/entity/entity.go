@@ -2,6 +2,7 @@ package entity
 
 import (
 	"codebase-app/pkg/types"
+	"time"
 )
 
 type CreateShopRequest struct {
@@ -10,10 +11,14 @@ type CreateShopRequest struct {
 	Name        string `json:"name" validate:"required" db:"name"`
 	Description string `json:"description" validate:"required,max=255" db:"description"`
 	Terms       string `json:"terms" validate:"required" db:"terms"`
+
+	// Currency the prices of the shop are in, it can not be changed later.
+	Currency string `json:"currency" validate:"omitempty,iso4217" db:"currency"`
 }
 
 type CreateShopResponse struct {
-	Id string `json:"id" db:"id"`
+	Id   string `json:"id" db:"id"`
+	Slug string `json:"slug" db:"slug"`
 }
 
 type GetShopRequest struct {
@@ -21,9 +26,11 @@ type GetShopRequest struct {
 }
 
 type GetShopResponse struct {
+	Slug        string        `json:"slug" db:"slug"`
 	Name        string        `json:"name" db:"name"`
 	Description string        `json:"description" db:"description"`
 	Terms       string        `json:"terms" db:"terms"`
+	Currency    string        `json:"currency" db:"currency"`
 	Products    []ProductItem `json:"products"`
 }
 
@@ -43,13 +50,19 @@ type UpdateShopRequest struct {
 }
 
 type UpdateShopResponse struct {
-	Id string `json:"id" db:"id"`
+	Id   string `json:"id" db:"id"`
+	Slug string `json:"slug" db:"slug"`
 }
 
 type ShopsRequest struct {
 	UserId   string `prop:"user_id" validate:"uuid"`
 	Page     int    `query:"page" validate:"required"`
 	Paginate int    `query:"paginate" validate:"required"`
+
+	// Cursor switches the listing to keyset pagination when the query parameter
+	// is present, an empty cursor asks for the first page.
+	Cursor     string `query:"cursor"`
+	CursorMode bool   `query:"-" json:"-"`
 }
 
 func (r *ShopsRequest) SetDefault() {
@@ -64,6 +77,7 @@ func (r *ShopsRequest) SetDefault() {
 
 type ShopItem struct {
 	Id       string        `json:"id" db:"id"`
+	Slug     string        `json:"slug" db:"slug"`
 	Name     string        `json:"name" db:"name"`
 	Products []ProductItem `gorm:"foreignKey:ShopID"`
 }
@@ -74,46 +88,119 @@ type ShopsResponse struct {
 }
 
 type CreateProductRequest struct {
-	UserId      string `json:"userId" validate:"required"`
-	ShopId      string `json:"shopId" validate:"required"`
-	CategoryId  string `json:"categoryId" validate:"required"`
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	Price       int    `json:"price" validate:"required"`
-	Merk        string `json:"merk" validate:"required"`
-	Rating      int    `json:"rating" validate:"required"`
-	Stock       int    `json:"stock" validate:"required"`
-	ImageURL    string `json:"imageUrl"`
+	UserId      string      `json:"userId" validate:"required"`
+	ShopId      string      `json:"shopId" validate:"required"`
+	CategoryId  string      `json:"categoryId" validate:"required"`
+	Name        string      `json:"name" validate:"required"`
+	Description string      `json:"description" validate:"required"`
+	Price       types.Money `json:"price" validate:"required,gt=0"`
+	Merk        string      `json:"merk" validate:"required"`
+	Stock       int         `json:"stock" validate:"required_unless=Type bundle"`
+	ImageURL    string      `json:"imageUrl"`
+
+	// Status defaults to published, PublishAt schedules the launch.
+	Status    string     `json:"status" form:"status" validate:"omitempty,oneof=draft published"`
+	PublishAt *time.Time `json:"publishAt" form:"publishAt"`
+
+	// Tags are free-form labels next to the category, e.g. "handmade".
+	Tags []string `json:"tags" form:"tags"`
+
+	// Type bundle sells the Components together, the stock of a bundle is
+	// derived from theirs.
+	Type       string                   `json:"type" form:"type" validate:"omitempty,oneof=single bundle"`
+	Components []BundleComponentRequest `json:"components" form:"components" validate:"required_if=Type bundle,excluded_unless=Type bundle,max=10,unique=ProductId,dive"`
+
+	// Weight is in grams, the dimensions in centimeters and given together.
+	Weight *int `json:"weight" form:"weight" validate:"omitempty,min=1,max=1000000"`
+	Length *int `json:"length" form:"length" validate:"required_with=Width Height,omitempty,min=1,max=1000"`
+	Width  *int `json:"width" form:"width" validate:"required_with=Length Height,omitempty,min=1,max=1000"`
+	Height *int `json:"height" form:"height" validate:"required_with=Length Width,omitempty,min=1,max=1000"`
+
+	ImageURLs []string `json:"-"`
 }
 
 type CreateProductResponse struct {
-	Id          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Stock       int     `json:"stock"`
-	Rating      int     `json:"rating"`
-	Merk        string  `json:"merk"`
-	UserId      string  `validate:"uuid" db:"user_id"`
-	ShopId      string  `validate:"uuid" db:"shop_id"`
-	CategoryId  string  `validate:"uuid" db:"category_id"`
-	ImageURL    string  `json:"imageUrl"`
+	Id          string      `json:"id"`
+	Slug        string      `json:"slug"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       types.Money `json:"price"`
+	Stock       int         `json:"stock"`
+	Rating      float64     `json:"rating"`
+	ReviewCount int         `json:"reviewCount"`
+	Merk        string      `json:"merk"`
+	UserId      string      `validate:"uuid" db:"user_id"`
+	ShopId      string      `validate:"uuid" db:"shop_id"`
+	CategoryId  string      `validate:"uuid" db:"category_id"`
+	ImageURL    string      `json:"imageUrl"`
+
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publishAt"`
+	Tags      []string   `json:"tags"`
+
+	Type       string            `json:"type"`
+	Components []BundleComponent `json:"components,omitempty"`
 }
 
 type GetProductResponse struct {
 	ProductItem []ProductItem
 	Meta        types.Meta `json:"meta"`
+
+	// Facets are only counted for the first page in cursor mode, they are nil
+	// on the next pages.
+	Facets *ProductFacets `json:"facets"`
 }
 
 type GetProductRequest struct {
 	Page     int    `query:"page" validate:"required"`
 	Paginate int    `query:"paginate" validate:"required"`
 	Keyword  string `query:"keyword"`
-	Rating   int    `query:"rating"`
+	Rating   int    `query:"rating" validate:"omitempty,min=1,max=5"`
 	Merk     string `query:"merk"`
+	BrandId  string `query:"brand_id" validate:"omitempty,uuid"`
 	Category string `query:"category"`
+
+	// CategoryId matches the category and all of its descendants.
+	CategoryId string `query:"category_id" validate:"omitempty,uuid"`
+
+	// MinPrice and MaxPrice are in the default currency, the prices of the
+	// other currencies are converted with the exchange rates.
+	MinPrice  types.Money `query:"min_price" validate:"omitempty,gte=0"`
+	MaxPrice  types.Money `query:"max_price" validate:"omitempty,gte=0,gtefield=MinPrice"`
+	InStock   bool        `query:"in_stock"`
+	ShopId    string      `query:"shop_id" validate:"omitempty,uuid"`
+	MinRating int         `query:"min_rating" validate:"omitempty,min=1,max=5"`
+	Sort      string      `query:"sort" validate:"omitempty,oneof=relevance price_asc price_desc newest rating name"`
+
+	// Cursor switches the listing to keyset pagination when the query parameter
+	// is present, an empty cursor asks for the first page.
+	Cursor     string `query:"cursor"`
+	CursorMode bool   `query:"-" json:"-"`
+
+	// AllStatuses drops the public visibility rule, for the seller's own views.
+	AllStatuses bool `query:"-" json:"-"`
+
+	// Attributes filters on attribute values by code, read from attr[code]
+	// query parameters. Values of the same code are alternatives.
+	Attributes map[string][]string `query:"-" json:"-" validate:"max=10,dive,keys,max=50,endkeys,max=10,dive,max=255"`
+
+	// Tags keeps the products having all of the given tags.
+	Tags []string `query:"tags" validate:"max=10,dive,max=255"`
+
+	// Currency adds indicative prices converted to it. The price filters and
+	// sorting use the default currency whatever it is.
+	Currency string `query:"currency" validate:"omitempty,iso4217"`
 }
 
+const (
+	SortRelevance = "relevance"
+	SortPriceAsc  = "price_asc"
+	SortPriceDesc = "price_desc"
+	SortNewest    = "newest"
+	SortRating    = "rating"
+	SortName      = "name"
+)
+
 func (r *GetProductRequest) SetDefault() {
 	if r.Page < 1 {
 		r.Page = 1
@@ -125,46 +212,125 @@ func (r *GetProductRequest) SetDefault() {
 }
 
 type ProductItem struct {
-	Id          string  `json:"id" db:"id"`
-	Name        string  `json:"name" db:"name"`
-	ShopId      string  `json:"shopId" db:"shop_id"`
-	Description string  `json:"description" db:"description"`
-	Price       float64 `json:"price" db:"price"`
-	Merk        string  `json:"merk" db:"merk"`
-	Rating      int     `json:"rating" db:"rating"`
-	Stock       int     `json:"stock" db:"stock"`
-	UserId      string  `json:"userId" db:"user_id"`
-	Category    string  `json:"category"`
-	ImageURL    string  `json:"imageUrl" db:"image_url"`
+	Id          string      `json:"id" db:"id"`
+	Slug        string      `json:"slug" db:"slug"`
+	Name        string      `json:"name" db:"name"`
+	ShopId      string      `json:"shopId" db:"shop_id"`
+	Description string      `json:"description" db:"description"`
+	Price       types.Money `json:"price" db:"price"`
+	Merk        string      `json:"merk" db:"merk"`
+	Rating      float64     `json:"rating" db:"rating"`
+	ReviewCount int         `json:"reviewCount" db:"review_count"`
+	Stock       int         `json:"stock" db:"stock"`
+	UserId      string      `json:"userId" db:"user_id"`
+	Category    string      `json:"category"`
+	ImageURL    string      `json:"imageUrl" db:"image_url"`
+	MinPrice    types.Money `json:"minPrice" db:"min_price"`
+	MaxPrice    types.Money `json:"maxPrice" db:"max_price"`
+	TotalStock  int         `json:"totalStock" db:"total_stock"`
+	Type        string      `json:"type" db:"type"`
+
+	// Price is the original price, EffectivePrice the one paid right now.
+	// SaleEndsAt is set while a sale with an end runs.
+	EffectivePrice types.Money `json:"effectivePrice" db:"effective_price"`
+	SaleEndsAt     *time.Time  `json:"saleEndsAt,omitempty" db:"sale_ends_at"`
+
+	// Currency is the one of the shop, Converted is set when another one is
+	// asked for.
+	Currency  string          `json:"currency,omitempty" db:"currency"`
+	Converted *ConvertedPrice `json:"converted,omitempty"`
+
+	// Status and PublishAt are only filled in the seller's own listings.
+	Status    string     `json:"status,omitempty" db:"status"`
+	PublishAt *time.Time `json:"publishAt,omitempty" db:"publish_at"`
+
+	Variants []VariantItem  `json:"variants"`
+	Images   []ProductImage `json:"images"`
+	Tags     []string       `json:"tags"`
 }
 
 type GetProductIdRequest struct {
 	Id string `validate:"uuid" db:"id"`
+
+	// UserId is the optional viewer, the owner can see unpublished products.
+	UserId string `prop:"user_id" validate:"omitempty,uuid" db:"user_id"`
+
+	// Currency adds indicative prices converted to it.
+	Currency string `query:"currency" validate:"omitempty,iso4217"`
 }
 
 type GetProductIdResponse struct {
-	Id          string  `json:"id" db:"id"`
-	Name        string  `json:"name" db:"name"`
-	ShopId      string  `json:"shopId" db:"shop_id"`
-	UserId      string  `json:"userId" db:"user_id"`
-	Price       float64 `json:"price" db:"price"`
-	Stock       int     `json:"stock" db:"stock"`
-	Description string  `json:"description" db:"description"`
-	ImageURL    string  `json:"imageUrl" db:"image_url"`
+	Id          string      `json:"id" db:"id"`
+	Slug        string      `json:"slug" db:"slug"`
+	Name        string      `json:"name" db:"name"`
+	ShopId      string      `json:"shopId" db:"shop_id"`
+	UserId      string      `json:"userId" db:"user_id"`
+	CategoryId  string      `json:"categoryId" db:"category_id"`
+	Price       types.Money `json:"price" db:"price"`
+	Stock       int         `json:"stock" db:"stock"`
+	Rating      float64     `json:"rating" db:"rating"`
+	ReviewCount int         `json:"reviewCount" db:"review_count"`
+	Description string      `json:"description" db:"description"`
+	ImageURL    string      `json:"imageUrl" db:"image_url"`
+	MinPrice    types.Money `json:"minPrice" db:"min_price"`
+	MaxPrice    types.Money `json:"maxPrice" db:"max_price"`
+	TotalStock  int         `json:"totalStock" db:"total_stock"`
+
+	Status    string     `json:"status" db:"status"`
+	PublishAt *time.Time `json:"publishAt" db:"publish_at"`
+	BrandId   *string    `json:"brandId" db:"brand_id"`
+
+	EffectivePrice types.Money  `json:"effectivePrice" db:"effective_price"`
+	SalePrice      *types.Money `json:"salePrice" db:"sale_price"`
+	SaleStartsAt   *time.Time   `json:"saleStartsAt" db:"sale_starts_at"`
+	SaleEndsAt     *time.Time   `json:"saleEndsAt" db:"sale_ends_at"`
+
+	Currency  string          `json:"currency" db:"currency"`
+	Converted *ConvertedPrice `json:"converted,omitempty"`
+
+	// Weight and VolumetricWeight are in grams, the dimensions in centimeters.
+	Weight           *int `json:"weight" db:"weight"`
+	Length           *int `json:"length" db:"length"`
+	Width            *int `json:"width" db:"width"`
+	Height           *int `json:"height" db:"height"`
+	VolumetricWeight *int `json:"volumetricWeight"`
+
+	Variants    []VariantItem        `json:"variants"`
+	Images      []ProductImage       `json:"images"`
+	Breadcrumbs []CategoryBreadcrumb `json:"breadcrumbs"`
+	Attributes  []ProductAttribute   `json:"attributes"`
+	Tags        []string             `json:"tags"`
+
+	Type       string            `json:"type" db:"type"`
+	Components []BundleComponent `json:"components,omitempty"`
+	PriceTiers []PriceTier       `json:"priceTiers"`
 }
 
 type UpdateProductRequest struct {
-	UserId      string `prop:"user_id" validate:"uuid" db:"user_id"`
-	Id          string `params:"id" validate:"uuid" db:"id"`
-	Name        string `json:"name" validate:"required" db:"name"`
-	Description string `json:"description" validate:"required" db:"description"`
-	Price       int    `json:"price" validate:"required" db:"price"`
-	Stock       int    `json:"stock" validate:"required" db:"stock"`
-	ImageURL    string `json:"imageUrl" db:"image_url"`
+	UserId      string      `prop:"user_id" validate:"uuid" db:"user_id"`
+	Id          string      `params:"id" validate:"uuid" db:"id"`
+	Name        string      `json:"name" validate:"required" db:"name"`
+	Description string      `json:"description" validate:"required" db:"description"`
+	Price       types.Money `json:"price" validate:"required,gt=0" db:"price"`
+	Stock       int         `json:"stock" validate:"required" db:"stock"`
+	ImageURL    string      `json:"imageUrl" db:"image_url"`
+
+	// Tags replaces the tags of the product, they are kept when it is omitted.
+	Tags *[]string `json:"tags" form:"tags"`
+
+	// Weight is in grams, the dimensions in centimeters and given together.
+	// They are kept when omitted.
+	Weight *int `json:"weight" form:"weight" validate:"omitempty,min=1,max=1000000" db:"weight"`
+	Length *int `json:"length" form:"length" validate:"required_with=Width Height,omitempty,min=1,max=1000" db:"length"`
+	Width  *int `json:"width" form:"width" validate:"required_with=Length Height,omitempty,min=1,max=1000" db:"width"`
+	Height *int `json:"height" form:"height" validate:"required_with=Length Width,omitempty,min=1,max=1000" db:"height"`
+
+	ImageURLs []string `json:"-"`
 }
 
 type UpdateProductResponse struct {
-	Id string `json:"id" db:"id"`
+	Id   string `json:"id" db:"id"`
+	Slug string `json:"slug" db:"slug"`
 }
 
 type DeleteProductRequest struct {
@@ -173,20 +339,23 @@ type DeleteProductRequest struct {
 }
 
 type CreateCategoryRequest struct {
-	UserId string `json:"userId" validate:"required"`
-	Name   string `json:"name" validate:"required" db:"name"`
+	UserId   string `json:"userId" validate:"required"`
+	Name     string `json:"name" validate:"required" db:"name"`
+	ParentId string `json:"parentId" validate:"omitempty,uuid" db:"parent_id"`
 }
 
 type CreateCategoryResponse struct {
-	Id     string `json:"id" db:"id"`
-	Name   string `json:"name" db:"name"`
-	UserId string `json:"userId" db:"user_id"`
+	Id       string  `json:"id" db:"id"`
+	Name     string  `json:"name" db:"name"`
+	UserId   string  `json:"userId" db:"user_id"`
+	ParentId *string `json:"parentId" db:"parent_id"`
 }
 
 type CategoryItem struct {
-	Id     string `json:"id" db:"id"`
-	Name   string `json:"name" db:"name"`
-	UserId string `json:"userId" db:"user_id"`
+	Id       string  `json:"id" db:"id"`
+	Name     string  `json:"name" db:"name"`
+	UserId   string  `json:"userId" db:"user_id"`
+	ParentId *string `json:"parentId" db:"parent_id"`
 }
 
 type GetCategoryResponse struct {
@@ -203,12 +372,29 @@ type GetCategoryIdRequest struct {
 }
 
 type GetcategoryIdResponse struct {
-	Id     string `json:"id" db:"id"`
-	Name   string `json:"name" db:"name"`
-	UserId string `json:"userId" db:"user_id"`
+	Id       string  `json:"id" db:"id"`
+	Name     string  `json:"name" db:"name"`
+	UserId   string  `json:"userId" db:"user_id"`
+	ParentId *string `json:"parentId" db:"parent_id"`
 }
 
 type DeleteCategoryRequest struct {
 	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
 	Id     string `validate:"uuid" db:"id"`
+
+	// ReassignTo moves the products of the deleted category to this category.
+	ReassignTo string `query:"reassign_to" validate:"omitempty,uuid,nefield=Id"`
+}
+
+type UpdateCategoryRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+	Id     string `params:"id" validate:"uuid" db:"id"`
+	Name   string `json:"name" validate:"required,max=255" db:"name"`
+}
+
+type UpdateCategoryResponse struct {
+	Id       string  `json:"id" db:"id"`
+	Name     string  `json:"name" db:"name"`
+	UserId   string  `json:"userId" db:"user_id"`
+	ParentId *string `json:"parentId" db:"parent_id"`
 }
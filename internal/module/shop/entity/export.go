@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"context"
+	"io"
+	"time"
+)
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+type ExportProductsRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+	ShopId string `params:"id" validate:"uuid"`
+	Format string `query:"format" validate:"omitempty,oneof=csv ndjson"`
+
+	// Filter takes the same query parameters as the product listing, paging
+	// and cursor are ignored.
+	Filter GetProductRequest `json:"-" validate:"-"`
+}
+
+func (r *ExportProductsRequest) SetDefault() {
+	if r.Format == "" {
+		r.Format = ExportFormatCSV
+	}
+
+	r.Filter.SetDefault()
+	r.Filter.ShopId = r.ShopId
+	// the seller exports drafts and archived products as well
+	r.Filter.AllStatuses = true
+}
+
+type ExportProductItem struct {
+	Id          string      `json:"id" db:"id"`
+	Name        string      `json:"name" db:"name"`
+	Description string      `json:"description" db:"description"`
+	Merk        string      `json:"merk" db:"merk"`
+	CategoryId  string      `json:"categoryId" db:"category_id"`
+	Category    string      `json:"category" db:"category_name"`
+	Price       types.Money `json:"price" db:"price"`
+	MinPrice    types.Money `json:"minPrice" db:"min_price"`
+	MaxPrice    types.Money `json:"maxPrice" db:"max_price"`
+	Stock       int         `json:"stock" db:"stock"`
+	TotalStock  int         `json:"totalStock" db:"total_stock"`
+	Rating      float64     `json:"rating" db:"rating"`
+	ReviewCount int         `json:"reviewCount" db:"review_count"`
+	ImageURL    string      `json:"imageUrl" db:"image_url"`
+	ImageURLs   []string    `json:"imageUrls"`
+
+	Status    string     `json:"status" db:"status"`
+	PublishAt *time.Time `json:"publishAt" db:"publish_at"`
+}
+
+// ExportWriter writes the export to w, it is called once the response headers
+// are sent so it must not rely on the request context.
+type ExportWriter func(ctx context.Context, w io.Writer) error
@@ -0,0 +1,24 @@
+package entity
+
+type FacetBucket struct {
+	Value string `json:"value" db:"value"`
+	Count int    `json:"count" db:"count"`
+}
+
+type PriceRangeBucket struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int      `json:"count"`
+}
+
+type ProductFacets struct {
+	Merk       []FacetBucket      `json:"merk"`
+	Category   []FacetBucket      `json:"category"`
+	Rating     []FacetBucket      `json:"rating"`
+	PriceRange []PriceRangeBucket `json:"priceRange"`
+}
+
+// PriceRangeBounds are the lower bounds of the price range facet buckets, each
+// bucket ends where the next one starts and the last one is open ended. They
+// are in the default currency.
+var PriceRangeBounds = []float64{0, 50000, 100000, 250000, 500000, 1000000}
@@ -0,0 +1,45 @@
+package entity
+
+type ProductImage struct {
+	Id        string `json:"id" db:"id"`
+	ProductId string `json:"productId" db:"product_id"`
+	URL       string `json:"url" db:"url"`
+	Position  int    `json:"position" db:"position"`
+	IsPrimary bool   `json:"isPrimary" db:"is_primary"`
+}
+
+type GetProductImagesRequest struct {
+	// UserId is the optional viewer, the owner can see unpublished products.
+	UserId    string `prop:"user_id" validate:"omitempty,uuid"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+}
+
+type GetProductImagesResponse struct {
+	Images []ProductImage `json:"images"`
+}
+
+type AddProductImagesRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+
+	ImageURLs []string `json:"-" validate:"required,min=1"`
+}
+
+type ReorderProductImagesRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+
+	ImageIds []string `json:"imageIds" validate:"required,min=1,unique_in_slice,dive,uuid"`
+}
+
+type SetPrimaryProductImageRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"image_id" validate:"uuid" db:"id"`
+}
+
+type DeleteProductImageRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"image_id" validate:"uuid" db:"id"`
+}
@@ -0,0 +1,35 @@
+package entity
+
+const (
+	ImportStatusImported = "imported"
+	ImportStatusFailed   = "failed"
+)
+
+type ImportProductsRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+	ShopId string `json:"shopId" form:"shopId" validate:"required,uuid"`
+
+	FileName string `json:"-"`
+	File     []byte `json:"-"`
+}
+
+// ImportProductRow is a parsed spreadsheet row, Row is the line number in the
+// uploaded file.
+type ImportProductRow struct {
+	Row     int
+	Product CreateProductRequest
+}
+
+type ImportRowResult struct {
+	Row       int                 `json:"row"`
+	Status    string              `json:"status"`
+	ProductId string              `json:"productId,omitempty"`
+	Errors    map[string][]string `json:"errors,omitempty"`
+}
+
+type ImportProductsResponse struct {
+	TotalRows int               `json:"totalRows"`
+	Imported  int               `json:"imported"`
+	Failed    int               `json:"failed"`
+	Rows      []ImportRowResult `json:"rows"`
+}
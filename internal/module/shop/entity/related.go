@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"strings"
+)
+
+// RelatedCandidate holds what the related products ranking looks at, for the
+// product being viewed as well as for every candidate.
+type RelatedCandidate struct {
+	Id               string      `db:"id"`
+	Slug             string      `db:"slug"`
+	Name             string      `db:"name"`
+	ShopId           string      `db:"shop_id"`
+	CategoryId       string      `db:"category_id"`
+	ParentCategoryId *string     `db:"parent_category_id"`
+	BrandId          *string     `db:"brand_id"`
+	Merk             string      `db:"merk"`
+	Price            types.Money `db:"price"`
+	// BasePrice is Price in the default currency, nil while the rate of the
+	// shop currency is unknown. Prices are compared on it.
+	BasePrice   *types.Money `db:"base_price"`
+	Rating      float64      `db:"rating"`
+	ReviewCount int          `db:"review_count"`
+	ImageURL    string       `db:"image_url"`
+}
+
+type GetRelatedProductsRequest struct {
+	UserId string `validate:"omitempty,uuid" db:"user_id"`
+
+	Id    string `params:"id" validate:"uuid" db:"id"`
+	Limit int    `query:"limit" validate:"omitempty,min=1,max=50"`
+}
+
+func (r *GetRelatedProductsRequest) SetDefault() {
+	if r.Limit < 1 {
+		r.Limit = 10
+	}
+}
+
+type RelatedProduct struct {
+	Id          string      `json:"id"`
+	Slug        string      `json:"slug"`
+	Name        string      `json:"name"`
+	ShopId      string      `json:"shopId"`
+	Merk        string      `json:"merk"`
+	Price       types.Money `json:"price"`
+	Rating      float64     `json:"rating"`
+	ReviewCount int         `json:"reviewCount"`
+	ImageURL    string      `json:"imageUrl"`
+	Score       float64     `json:"score"`
+}
+
+type GetRelatedProductsResponse struct {
+	Items []RelatedProduct `json:"items"`
+}
+
+// NameTokens splits a product name into the lowercase words the related
+// products text similarity compares, each word once and in order of first
+// appearance. Single characters are dropped.
+func NameTokens(name string) []string {
+	var (
+		sb     strings.Builder
+		seen   = make(map[string]bool)
+		tokens = make([]string, 0)
+	)
+
+	for _, c := range strings.ToLower(name) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c > 127:
+			sb.WriteRune(c)
+		default:
+			sb.WriteByte(' ')
+		}
+	}
+
+	for _, w := range strings.Fields(sb.String()) {
+		if len([]rune(w)) < 2 || seen[w] {
+			continue
+		}
+		seen[w] = true
+		tokens = append(tokens, w)
+	}
+
+	return tokens
+}
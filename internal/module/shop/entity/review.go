@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
+
+type ReviewItem struct {
+	Id          string     `json:"id" db:"id"`
+	ProductId   string     `json:"productId" db:"product_id"`
+	UserId      string     `json:"userId" db:"user_id"`
+	Rating      int        `json:"rating" db:"rating"`
+	Body        string     `json:"body" db:"body"`
+	Photos      []string   `json:"photos"`
+	SellerReply *string    `json:"sellerReply" db:"seller_reply"`
+	RepliedAt   *time.Time `json:"repliedAt" db:"replied_at"`
+	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+type GetReviewsRequest struct {
+	// UserId is the optional viewer, the owner can see unpublished products.
+	UserId    string `prop:"user_id" validate:"omitempty,uuid"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Page      int    `query:"page" validate:"required"`
+	Paginate  int    `query:"paginate" validate:"required"`
+	Rating    int    `query:"rating" validate:"omitempty,min=1,max=5"`
+}
+
+func (r *GetReviewsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type GetReviewsResponse struct {
+	Rating      float64      `json:"rating" db:"rating"`
+	ReviewCount int          `json:"reviewCount" db:"review_count"`
+	Items       []ReviewItem `json:"items"`
+	Meta        types.Meta   `json:"meta"`
+}
+
+type CreateReviewRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+
+	Rating int    `json:"rating" form:"rating" validate:"required,min=1,max=5" db:"rating"`
+	Body   string `json:"body" form:"body" validate:"max=2000" db:"body"`
+
+	PhotoURLs []string `json:"-" validate:"max=5"`
+}
+
+type CreateReviewResponse struct {
+	Id string `json:"id" db:"id"`
+}
+
+type UpdateReviewRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"review_id" validate:"uuid" db:"id"`
+
+	Rating int    `json:"rating" form:"rating" validate:"required,min=1,max=5" db:"rating"`
+	Body   string `json:"body" form:"body" validate:"max=2000" db:"body"`
+
+	PhotoURLs []string `json:"-" validate:"max=5"`
+}
+
+type UpdateReviewResponse struct {
+	Id string `json:"id" db:"id"`
+}
+
+type DeleteReviewRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"review_id" validate:"uuid" db:"id"`
+}
+
+type ReplyReviewRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"review_id" validate:"uuid" db:"id"`
+
+	Reply string `json:"reply" validate:"required,max=2000" db:"seller_reply"`
+}
+
+type ReplyReviewResponse struct {
+	Id string `json:"id" db:"id"`
+}
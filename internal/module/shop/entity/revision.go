@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
+
+const (
+	RevisionActionCreate   = "create"
+	RevisionActionUpdate   = "update"
+	RevisionActionStatus   = "status"
+	RevisionActionDelete   = "delete"
+	RevisionActionRestore  = "restore"
+	RevisionActionRollback = "rollback"
+	// RevisionActionBrand is a merk changed by an admin renaming or merging
+	// the brand of the product.
+	RevisionActionBrand = "brand"
+)
+
+// ProductRevisionFields are the fields kept in a revision snapshot, in the
+// order their changes are reported.
+var ProductRevisionFields = []string{
+	"name", "description", "price", "stock", "merk", "categoryId",
+	"imageUrl", "status", "publishAt", "deletedAt",
+	"weight", "length", "width", "height", "tags",
+}
+
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type ProductRevision struct {
+	Id         string        `json:"id" db:"id"`
+	ProductId  string        `json:"productId" db:"product_id"`
+	UserId     string        `json:"userId" db:"user_id"`
+	Action     string        `json:"action" db:"action"`
+	RollbackOf *string       `json:"rollbackOf" db:"rollback_of"`
+	CreatedAt  time.Time     `json:"createdAt" db:"created_at"`
+	Changes    []FieldChange `json:"changes"`
+}
+
+type GetProductRevisionsRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid"`
+	ProductId string `params:"id" validate:"uuid"`
+	Page      int    `query:"page" validate:"required"`
+	Paginate  int    `query:"paginate" validate:"required"`
+}
+
+func (r *GetProductRevisionsRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type GetProductRevisionsResponse struct {
+	Items []ProductRevision `json:"items"`
+	Meta  types.Meta        `json:"meta"`
+}
+
+type RollbackProductRequest struct {
+	UserId     string `prop:"user_id" validate:"uuid"`
+	ProductId  string `params:"id" validate:"uuid"`
+	RevisionId string `params:"revision_id" validate:"uuid"`
+}
+
+type RollbackProductResponse struct {
+	Id         string `json:"id"`
+	RevisionId string `json:"revisionId"`
+}
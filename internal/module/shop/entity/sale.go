@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
+
+type SetProductSaleRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid"`
+	ProductId string `params:"id" validate:"uuid"`
+
+	// StartsAt empty starts the sale right away, EndsAt empty never ends it.
+	SalePrice types.Money `json:"salePrice" validate:"required,gt=0"`
+	StartsAt  *time.Time  `json:"startsAt"`
+	EndsAt    *time.Time  `json:"endsAt"`
+}
+
+type DeleteProductSaleRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid"`
+	ProductId string `params:"id" validate:"uuid"`
+}
+
+type ProductSaleResponse struct {
+	ProductId      string       `json:"productId" db:"id"`
+	Price          types.Money  `json:"price" db:"price"`
+	EffectivePrice types.Money  `json:"effectivePrice" db:"effective_price"`
+	SalePrice      *types.Money `json:"salePrice" db:"sale_price"`
+	SaleStartsAt   *time.Time   `json:"saleStartsAt" db:"sale_starts_at"`
+	SaleEndsAt     *time.Time   `json:"saleEndsAt" db:"sale_ends_at"`
+}
+
+type PriceHistoryItem struct {
+	Id           string       `json:"id" db:"id"`
+	Price        types.Money  `json:"price" db:"price"`
+	SalePrice    *types.Money `json:"salePrice" db:"sale_price"`
+	SaleStartsAt *time.Time   `json:"saleStartsAt" db:"sale_starts_at"`
+	SaleEndsAt   *time.Time   `json:"saleEndsAt" db:"sale_ends_at"`
+	CreatedAt    time.Time    `json:"createdAt" db:"created_at"`
+}
+
+type GetPriceHistoryRequest struct {
+	// UserId is the optional viewer, the owner can see unpublished products.
+	UserId    string `prop:"user_id" validate:"omitempty,uuid"`
+	ProductId string `params:"id" validate:"uuid"`
+	Page      int    `query:"page" validate:"required"`
+	Paginate  int    `query:"paginate" validate:"required"`
+}
+
+func (r *GetPriceHistoryRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type GetPriceHistoryResponse struct {
+	Items []PriceHistoryItem `json:"items"`
+	Meta  types.Meta         `json:"meta"`
+}
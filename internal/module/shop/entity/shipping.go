@@ -0,0 +1,21 @@
+package entity
+
+import "math"
+
+// VolumetricDivisor is the number of cubic centimeters the couriers count as
+// one kilogram.
+const VolumetricDivisor = 6000
+
+// VolumetricWeight returns the weight in grams couriers bill a parcel of the
+// given dimensions in centimeters, rounded up. It is nil unless all three
+// dimensions are known.
+func VolumetricWeight(length, width, height *int) *int {
+	if length == nil || width == nil || height == nil {
+		return nil
+	}
+
+	volume := float64(*length) * float64(*width) * float64(*height)
+	grams := int(math.Ceil(volume * 1000 / VolumetricDivisor))
+
+	return &grams
+}
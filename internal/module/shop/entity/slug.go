@@ -0,0 +1,39 @@
+package entity
+
+type GetShopBySlugRequest struct {
+	Slug string `params:"slug" validate:"required,max=120" db:"slug"`
+}
+
+// GetShopBySlugResponse carries the shop when the slug is the current one.
+// For an old slug Redirected is set and only Id and the current Slug are filled.
+type GetShopBySlugResponse struct {
+	Id   string `json:"id" db:"id"`
+	Slug string `json:"slug" db:"slug"`
+
+	Redirected bool `json:"-"`
+
+	*GetShopResponse
+}
+
+type GetProductBySlugRequest struct {
+	UserId string `validate:"omitempty,uuid" db:"user_id"`
+
+	ShopSlug string `params:"shop_slug" validate:"required,max=120" db:"shop_slug"`
+	Slug     string `params:"slug" validate:"required,max=120" db:"slug"`
+
+	// Currency adds indicative prices converted to it.
+	Currency string `query:"currency" validate:"omitempty,iso4217"`
+}
+
+// GetProductBySlugResponse carries the product when both slugs are the current
+// ones. When either is old Redirected is set and only Id, ShopSlug and Slug are
+// filled.
+type GetProductBySlugResponse struct {
+	Id       string `json:"id" db:"id"`
+	ShopSlug string `json:"shopSlug" db:"shop_slug"`
+	Slug     string `json:"slug" db:"slug"`
+
+	Redirected bool `json:"-"`
+
+	*GetProductIdResponse
+}
@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+const (
+	ProductStatusDraft     = "draft"
+	ProductStatusPublished = "published"
+	ProductStatusArchived  = "archived"
+
+	// ProductStatusScheduled is never stored, it reports a published product
+	// whose publish_at is still in the future.
+	ProductStatusScheduled = "scheduled"
+)
+
+// productStatusTransitions lists the statuses a product may move to.
+var productStatusTransitions = map[string][]string{
+	ProductStatusDraft:     {ProductStatusPublished, ProductStatusArchived},
+	ProductStatusPublished: {ProductStatusDraft, ProductStatusArchived},
+	ProductStatusArchived:  {ProductStatusDraft},
+}
+
+// CanTransitionProductStatus reports whether a product in status from may be
+// moved to status to. Staying in the same status is allowed so a scheduled
+// launch can be rescheduled.
+func CanTransitionProductStatus(from, to string) bool {
+	if from == to {
+		return true
+	}
+
+	for _, s := range productStatusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EffectiveProductStatus returns the status shown to the seller.
+func EffectiveProductStatus(status string, publishAt *time.Time) string {
+	if status == ProductStatusPublished && publishAt != nil && publishAt.After(time.Now()) {
+		return ProductStatusScheduled
+	}
+
+	return status
+}
+
+type UpdateProductStatusRequest struct {
+	UserId string `prop:"user_id" validate:"uuid" db:"user_id"`
+	Id     string `params:"id" validate:"uuid" db:"id"`
+
+	Status string `json:"status" validate:"required,oneof=draft published archived" db:"status"`
+
+	// PublishAt schedules the launch, it is only accepted when publishing.
+	PublishAt *time.Time `json:"publishAt" db:"publish_at"`
+}
+
+type UpdateProductStatusResponse struct {
+	Id        string     `json:"id" db:"id"`
+	Status    string     `json:"status" db:"status"`
+	PublishAt *time.Time `json:"publishAt" db:"publish_at"`
+}
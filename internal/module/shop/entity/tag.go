@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"codebase-app/pkg"
+	"strings"
+)
+
+const (
+	// TagMaxLength is the size of tags.name.
+	TagMaxLength = 50
+	// TagMaxCount is the number of tags a product may have.
+	TagMaxCount = 20
+)
+
+// NormalizeTags turns the tags given by a seller or a buyer into the form they
+// are stored in, e.g. "Ramadan Sale" becomes "ramadan-sale". An entry may hold
+// several tags separated by commas. Empty and repeated tags are dropped, the
+// order is kept. The limits are checked on the result, an entry says nothing
+// about the number of tags it holds.
+func NormalizeTags(tags []string) []string {
+	var (
+		seen   = make(map[string]bool)
+		result = make([]string, 0, len(tags))
+	)
+
+	for _, entry := range tags {
+		for _, tag := range strings.Split(entry, ",") {
+			tag = pkg.Slugify(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
+
+type TagItem struct {
+	Name         string `json:"name" db:"name"`
+	ProductCount int    `json:"productCount" db:"product_count"`
+}
+
+type GetTagsRequest struct {
+	Keyword string `query:"keyword" validate:"max=50"`
+	ShopId  string `query:"shop_id" validate:"omitempty,uuid"`
+	Limit   int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (r *GetTagsRequest) SetDefault() {
+	if r.Limit < 1 {
+		r.Limit = 20
+	}
+}
+
+type GetTagsResponse struct {
+	Items []TagItem `json:"items"`
+}
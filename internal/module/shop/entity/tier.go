@@ -0,0 +1,57 @@
+package entity
+
+import "codebase-app/pkg/types"
+
+// PriceTier is a wholesale price, buying at least MinQuantity units costs
+// Price per unit.
+type PriceTier struct {
+	MinQuantity int         `json:"minQuantity" db:"min_quantity"`
+	Price       types.Money `json:"price" db:"price"`
+}
+
+type PriceTierRequest struct {
+	MinQuantity int         `json:"minQuantity" validate:"required,min=2,max=100000"`
+	Price       types.Money `json:"price" validate:"required,gt=0"`
+}
+
+type SetPriceTiersRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid"`
+	ProductId string `params:"id" validate:"uuid"`
+
+	// Tiers replaces the tiers of the product, an empty list removes them.
+	Tiers []PriceTierRequest `json:"tiers" validate:"max=10,unique=MinQuantity,dive"`
+}
+
+type SetPriceTiersResponse struct {
+	Tiers []PriceTier `json:"tiers"`
+}
+
+// PricedProduct is what a price quote is computed from.
+type PricedProduct struct {
+	Id             string      `db:"id"`
+	Price          types.Money `db:"price"`
+	EffectivePrice types.Money `db:"effective_price"`
+	TotalStock     int         `db:"total_stock"`
+	Tiers          []PriceTier
+}
+
+type GetPriceQuoteRequest struct {
+	// UserId is the optional viewer, the owner can see unpublished products.
+	UserId    string `prop:"user_id" validate:"omitempty,uuid"`
+	ProductId string `params:"id" validate:"uuid"`
+	Quantity  int    `query:"quantity" validate:"required,min=1,max=100000"`
+}
+
+type GetPriceQuoteResponse struct {
+	ProductId string      `json:"productId"`
+	Quantity  int         `json:"quantity"`
+	UnitPrice types.Money `json:"unitPrice"`
+	Total     types.Money `json:"total"`
+
+	// Tier is the wholesale tier applied, NextTier the next cheaper one.
+	Tier     *PriceTier `json:"tier"`
+	NextTier *PriceTier `json:"nextTier"`
+
+	// Available tells whether the product has enough stock for the quantity.
+	Available bool `json:"available"`
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"time"
+)
+
+const (
+	TrashShops      = "shops"
+	TrashProducts   = "products"
+	TrashCategories = "categories"
+)
+
+type TrashItem struct {
+	Id        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	ShopId    string    `json:"shopId,omitempty" db:"shop_id"`
+	ParentId  *string   `json:"parentId,omitempty" db:"parent_id"`
+	ImageURL  string    `json:"imageUrl,omitempty" db:"image_url"`
+	DeletedAt time.Time `json:"deletedAt" db:"deleted_at"`
+}
+
+type GetTrashRequest struct {
+	UserId   string `prop:"user_id" validate:"uuid"`
+	Kind     string `params:"kind" validate:"required,oneof=shops products categories"`
+	Page     int    `query:"page" validate:"required"`
+	Paginate int    `query:"paginate" validate:"required"`
+}
+
+func (r *GetTrashRequest) SetDefault() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+
+	if r.Paginate < 1 {
+		r.Paginate = 10
+	}
+}
+
+type GetTrashResponse struct {
+	Items []TrashItem `json:"items"`
+	Meta  types.Meta  `json:"meta"`
+}
+
+type RestoreTrashRequest struct {
+	UserId string `prop:"user_id" validate:"uuid"`
+	Kind   string `params:"kind" validate:"required,oneof=shops products categories"`
+	Id     string `params:"id" validate:"uuid"`
+}
+
+type RestoreTrashResponse struct {
+	Id string `json:"id" db:"id"`
+}
+
+type PurgeTrashRequest struct {
+	// Before is the cut off, rows deleted earlier are removed for good.
+	Before time.Time
+}
+
+type PurgeTrashResponse struct {
+	Shops      int
+	Products   int
+	Categories int
+
+	// KeptComponentIds are the products due for purging that stay because a
+	// bundle that is not purged still uses them.
+	KeptComponentIds []string
+
+	// ImageURLs are the images of the purged products, left for the caller to
+	// remove from the storage once the rows are gone.
+	ImageURLs []string
+}
@@ -0,0 +1,75 @@
+package entity
+
+import "codebase-app/pkg/types"
+
+type VariantOption struct {
+	Name  string `json:"name" db:"name"`
+	Value string `json:"value" db:"value"`
+}
+
+type OptionType struct {
+	Id     string   `json:"id" db:"id"`
+	Name   string   `json:"name" db:"name"`
+	Values []string `json:"values"`
+}
+
+type VariantItem struct {
+	Id        string          `json:"id" db:"id"`
+	ProductId string          `json:"productId" db:"product_id"`
+	Sku       string          `json:"sku" db:"sku"`
+	Price     *types.Money    `json:"price" db:"price"`
+	Stock     int             `json:"stock" db:"stock"`
+	ImageURL  string          `json:"imageUrl" db:"image_url"`
+	Options   []VariantOption `json:"options"`
+}
+
+type CreateVariantRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+
+	Sku      string            `json:"sku" form:"sku" validate:"required,max=100" db:"sku"`
+	Price    *types.Money      `json:"price" form:"price" validate:"omitempty,gt=0" db:"price"`
+	Stock    int               `json:"stock" form:"stock" validate:"gte=0" db:"stock"`
+	Options  map[string]string `json:"options" form:"-" validate:"required,min=1,dive,keys,required,max=100,endkeys,required,max=100"`
+	ImageURL string            `json:"imageUrl" db:"image_url"`
+
+	// OptionsJSON carries the options of a multipart request, which can't hold
+	// a map, as a JSON object, e.g. {"color":"red"}.
+	OptionsJSON string `json:"-" form:"options"`
+}
+
+type CreateVariantResponse struct {
+	Id string `json:"id" db:"id"`
+}
+
+type UpdateVariantRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"variant_id" validate:"uuid" db:"id"`
+
+	Sku      string       `json:"sku" form:"sku" validate:"required,max=100" db:"sku"`
+	Price    *types.Money `json:"price" form:"price" validate:"omitempty,gt=0" db:"price"`
+	Stock    int          `json:"stock" form:"stock" validate:"gte=0" db:"stock"`
+	ImageURL string       `json:"imageUrl" db:"image_url"`
+}
+
+type UpdateVariantResponse struct {
+	Id string `json:"id" db:"id"`
+}
+
+type DeleteVariantRequest struct {
+	UserId    string `prop:"user_id" validate:"uuid" db:"user_id"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+	Id        string `params:"variant_id" validate:"uuid" db:"id"`
+}
+
+type GetVariantsRequest struct {
+	// UserId is the optional viewer, the owner can see unpublished products.
+	UserId    string `prop:"user_id" validate:"omitempty,uuid"`
+	ProductId string `params:"id" validate:"uuid" db:"product_id"`
+}
+
+type GetVariantsResponse struct {
+	Options  []OptionType  `json:"options"`
+	Variants []VariantItem `json:"variants"`
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"bufio"
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/middleware"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+	"context"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *shopHandler) ExportProducts(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ExportProductsRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::ExportProducts - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := c.QueryParser(&req.Filter); err != nil {
+		log.Warn().Err(err).Msg("handler::ExportProducts - Parse filter query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.Filter.Attributes = attributeFilter(c)
+	req.UserId = l.UserId
+	req.ShopId = c.Params("id")
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::ExportProducts - Validate request query")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := v.Validate(&req.Filter); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::ExportProducts - Validate filter query")
+		code, errs := errmsg.Errors(err, &req.Filter)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	write, err := h.service.ExportProducts(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if req.Format == entity.ExportFormatNDJSON {
+		contentType = "application/x-ndjson"
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="products-%s.%s"`, req.ShopId, req.Format))
+
+	// the request context is recycled once the handler returns, the stream
+	// writer runs after that
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := write(context.Background(), w); err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("handler::ExportProducts - Failed to stream products")
+		}
+	})
+
+	return nil
+}
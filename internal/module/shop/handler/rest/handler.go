@@ -32,18 +32,64 @@ func NewShopHandler() *shopHandler {
 func (h *shopHandler) Register(router fiber.Router) {
 	router.Get("/shops", middleware.UserIdHeader, h.GetShops)
 	router.Post("/shops", middleware.UserIdHeader, h.CreateShop)
+	router.Get("/shops/slug/:slug", h.GetShopBySlug)
+	router.Get("/shops/slug/:shop_slug/products/:slug", middleware.OptionalUserIdHeader, h.GetProductBySlug)
 	router.Get("/shops/:id", h.GetShop)
 	router.Delete("/shops/:id", middleware.UserIdHeader, h.DeleteShop)
 	router.Patch("/shops/:id", middleware.UserIdHeader, h.UpdateShop)
-	router.Post("/products", middleware.UserIdHeader, middleware.UploadImageMiddleware, h.CreateProduct)
+	router.Get("/shops/:id/products/export", middleware.UserIdHeader, h.ExportProducts)
+	router.Post("/products", middleware.UserIdHeader, middleware.UploadImageMiddleware, middleware.UploadImagesMiddleware, h.CreateProduct)
+	router.Post("/products/import", middleware.UserIdHeader, h.ImportProducts)
 	router.Get("/products/all", middleware.UserIdHeader, h.GetAllProduct)
-	router.Get("/products/:id", h.GetProductByid)
-	router.Patch("/products/:id", middleware.UserIdHeader, middleware.UploadImageMiddleware, h.UpdateProduct)
+	router.Get("/products/:id", middleware.OptionalUserIdHeader, h.GetProductByid)
+	router.Patch("/products/:id", middleware.UserIdHeader, middleware.UploadImageMiddleware, middleware.UploadImagesMiddleware, h.UpdateProduct)
 	router.Delete("/products/:id", middleware.UserIdHeader, h.DeleteProduct)
+	router.Get("/products/:id/related", middleware.OptionalUserIdHeader, h.GetRelatedProducts)
+	router.Patch("/products/:id/status", middleware.UserIdHeader, h.UpdateProductStatus)
+	router.Put("/products/:id/attributes", middleware.UserIdHeader, h.SetProductAttributes)
+	router.Put("/products/:id/components", middleware.UserIdHeader, h.SetBundleComponents)
+	router.Put("/products/:id/sale", middleware.UserIdHeader, h.SetProductSale)
+	router.Delete("/products/:id/sale", middleware.UserIdHeader, h.DeleteProductSale)
+	router.Get("/products/:id/price-history", middleware.OptionalUserIdHeader, h.GetPriceHistory)
+	router.Put("/products/:id/price-tiers", middleware.UserIdHeader, h.SetPriceTiers)
+	router.Get("/products/:id/quote", middleware.OptionalUserIdHeader, h.GetPriceQuote)
+	router.Get("/products/:id/revisions", middleware.UserIdHeader, h.GetProductRevisions)
+	router.Post("/products/:id/revisions/:revision_id/rollback", middleware.UserIdHeader, h.RollbackProduct)
+	router.Get("/products/:id/variants", middleware.OptionalUserIdHeader, h.GetVariants)
+	router.Post("/products/:id/variants", middleware.UserIdHeader, middleware.UploadImageMiddleware, h.CreateVariant)
+	router.Patch("/products/:id/variants/:variant_id", middleware.UserIdHeader, middleware.UploadImageMiddleware, h.UpdateVariant)
+	router.Delete("/products/:id/variants/:variant_id", middleware.UserIdHeader, h.DeleteVariant)
+	router.Get("/products/:id/images", middleware.OptionalUserIdHeader, h.GetProductImages)
+	router.Post("/products/:id/images", middleware.UserIdHeader, middleware.UploadImagesMiddleware, h.AddProductImages)
+	router.Patch("/products/:id/images/order", middleware.UserIdHeader, h.ReorderProductImages)
+	router.Patch("/products/:id/images/:image_id/primary", middleware.UserIdHeader, h.SetPrimaryProductImage)
+	router.Delete("/products/:id/images/:image_id", middleware.UserIdHeader, h.DeleteProductImage)
+	router.Get("/products/:id/reviews", middleware.OptionalUserIdHeader, h.GetReviews)
+	router.Post("/products/:id/reviews", middleware.UserIdHeader, middleware.UploadImagesMiddleware, h.CreateReview)
+	router.Patch("/products/:id/reviews/:review_id", middleware.UserIdHeader, middleware.UploadImagesMiddleware, h.UpdateReview)
+	router.Delete("/products/:id/reviews/:review_id", middleware.UserIdHeader, h.DeleteReview)
+	router.Post("/products/:id/reviews/:review_id/reply", middleware.UserIdHeader, h.ReplyReview)
 	router.Post("/categories", middleware.UserIdHeader, h.CreateCategory)
 	router.Get("/categories", middleware.UserIdHeader, h.GetCategory)
+	router.Get("/categories/tree", middleware.UserIdHeader, h.GetCategoryTree)
+	router.Patch("/categories/:id/move", middleware.UserIdHeader, h.MoveCategory)
+	router.Get("/categories/:id/attributes", middleware.UserIdHeader, h.GetCategoryAttributes)
+	router.Post("/categories/:id/attributes", middleware.UserIdHeader, h.CreateCategoryAttribute)
+	router.Patch("/categories/:id/attributes/:attribute_id", middleware.UserIdHeader, h.UpdateCategoryAttribute)
+	router.Delete("/categories/:id/attributes/:attribute_id", middleware.UserIdHeader, h.DeleteCategoryAttribute)
 	router.Get("/categories/:id", middleware.UserIdHeader, h.GetCategoryId)
+	router.Patch("/categories/:id", middleware.UserIdHeader, h.UpdateCategory)
 	router.Delete("/categories/:id", middleware.UserIdHeader, h.DeleteCategory)
+	router.Get("/brands", h.GetBrands)
+	router.Get("/brands/:id", h.GetBrand)
+	router.Patch("/brands/:id", middleware.AuthBearer, middleware.AuthRole([]string{"admin"}), h.UpdateBrand)
+	router.Post("/brands/:id/aliases", middleware.AuthBearer, middleware.AuthRole([]string{"admin"}), h.AddBrandAlias)
+	router.Delete("/brands/:id/aliases/:alias", middleware.AuthBearer, middleware.AuthRole([]string{"admin"}), h.DeleteBrandAlias)
+	router.Get("/tags", h.GetTags)
+	router.Get("/exchange-rates", h.GetExchangeRates)
+	router.Put("/exchange-rates", middleware.AuthBearer, middleware.AuthRole([]string{"admin"}), h.SetExchangeRates)
+	router.Get("/trash/:kind", middleware.UserIdHeader, h.GetTrash)
+	router.Post("/trash/:kind/:id/restore", middleware.UserIdHeader, h.RestoreTrash)
 
 }
 
@@ -172,6 +218,7 @@ func (h *shopHandler) GetShops(c *fiber.Ctx) error {
 	}
 
 	req.UserId = l.UserId
+	req.CursorMode = c.Context().QueryArgs().Has("cursor")
 	req.SetDefault()
 
 	if err := v.Validate(req); err != nil {
@@ -212,6 +259,10 @@ func (h *shopHandler) CreateProduct(c *fiber.Ctx) error {
 		req.ImageURL = ""
 	}
 
+	if imageURLs, ok := c.Locals("imageURLs").([]string); ok {
+		req.ImageURLs = imageURLs
+	}
+
 	if err := v.Validate(req); err != nil {
 		log.Warn().Err(err).Any("payload", req).Msg("handler::CreateProduct - Validate request body")
 		code, errs := errmsg.Errors(err, req)
@@ -240,6 +291,8 @@ func (h *shopHandler) GetAllProduct(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
+	req.Attributes = attributeFilter(c)
+	req.CursorMode = c.Context().QueryArgs().Has("cursor")
 	req.SetDefault()
 
 	if err := v.Validate(req); err != nil {
@@ -266,6 +319,11 @@ func (h *shopHandler) GetProductByid(c *fiber.Ctx) error {
 	)
 
 	req.Id = c.Params("id")
+	req.Currency = c.Query("currency")
+
+	if userId, ok := c.Locals("user_id").(string); ok {
+		req.UserId = userId
+	}
 
 	if err := v.Validate(req); err != nil {
 		log.Warn().Err(err).Any("payload", req).Msg("handler::GetProductByid - Validate request body")
@@ -297,6 +355,14 @@ func (h *shopHandler) UpdateProduct(c *fiber.Ctx) error {
 	req.UserId = l.UserId
 	req.Id = c.Params("id")
 
+	if imageURL, ok := c.Locals("imageURL").(string); ok && imageURL != "" {
+		req.ImageURL = imageURL
+	}
+
+	if imageURLs, ok := c.Locals("imageURLs").([]string); ok {
+		req.ImageURLs = imageURLs
+	}
+
 	if err := v.Validate(req); err != nil {
 		log.Warn().Err(err).Any("payload", req).Msg("handler::UpdateProduct - Validate request body")
 		code, errs := errmsg.Errors(err, req)
@@ -352,6 +418,8 @@ func (h *shopHandler) CreateCategory(c *fiber.Ctx) error {
 	req.UserId = l.UserId
 	if err := v.Validate(req); err != nil {
 		log.Warn().Err(err).Any("payload", req).Msg("handler::CreateCategory - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
 	}
 
 	resp, err := h.service.CreateCategory(ctx, req)
@@ -413,11 +481,18 @@ func (h *shopHandler) DeleteCategory(c *fiber.Ctx) error {
 		l   = middleware.GetLocals(c)
 	)
 
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::DeleteCategory - Parse request query")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
 	req.Id = c.Params("id")
 	req.UserId = l.UserId
 
 	if err := v.Validate(req); err != nil {
-		log.Warn().Err(err).Any("payload", req).Msg("handler::GetProductByid - Validate request body")
+		log.Warn().Err(err).Any("payload", req).Msg("handler::DeleteCategory - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
 	}
 
 	err := h.service.DeletCategory(ctx, req)
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/middleware"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *shopHandler) ImportProducts(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ImportProductsRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+		l   = middleware.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::ImportProducts - Parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.UserId
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::ImportProducts - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Warn().Err(err).Msg("handler::ImportProducts - Get uploaded file")
+		code, errs := errmsg.Errors[error](errmsg.NewCustomErrors(400, errmsg.WithErrors("file", "file harus diisi.")))
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		log.Error().Err(err).Msg("handler::ImportProducts - Open uploaded file")
+		return c.Status(fiber.StatusInternalServerError).JSON(response.Error(err))
+	}
+	defer f.Close()
+
+	req.FileName = file.Filename
+	req.File, err = io.ReadAll(f)
+	if err != nil {
+		log.Error().Err(err).Msg("handler::ImportProducts - Read uploaded file")
+		return c.Status(fiber.StatusInternalServerError).JSON(response.Error(err))
+	}
+
+	resp, err := h.service.ImportProducts(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+func (h *shopHandler) GetShopBySlug(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetShopBySlugRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.Slug = c.Params("slug")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetShopBySlug - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetShopBySlug(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	// an old slug points the client to the current one
+	if resp.Redirected {
+		c.Location(strings.TrimSuffix(c.Path(), req.Slug) + resp.Slug)
+		return c.Status(fiber.StatusMovedPermanently).JSON(response.Success(resp, ""))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *shopHandler) GetProductBySlug(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetProductBySlugRequest)
+		ctx = c.Context()
+		v   = adapter.Adapters.Validator
+	)
+
+	req.ShopSlug = c.Params("shop_slug")
+	req.Slug = c.Params("slug")
+	req.Currency = c.Query("currency")
+
+	if userId, ok := c.Locals("user_id").(string); ok {
+		req.UserId = userId
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("payload", req).Msg("handler::GetProductBySlug - Validate request body")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetProductBySlug(ctx, req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	// an old shop or product slug points the client to the current ones
+	if resp.Redirected {
+		base := strings.TrimSuffix(c.Path(), req.ShopSlug+"/products/"+req.Slug)
+		location := base + resp.ShopSlug + "/products/" + resp.Slug
+		if query := string(c.Request().URI().QueryString()); query != "" {
+			location += "?" + query
+		}
+		c.Location(location)
+		return c.Status(fiber.StatusMovedPermanently).JSON(response.Success(resp, ""))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
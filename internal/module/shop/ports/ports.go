@@ -20,6 +20,58 @@ type ShopRepository interface {
 	GetCategory(ctx context.Context, req *entity.GetCategoryRequest) (*entity.GetCategoryResponse, error)
 	GetCategoryId(ctx context.Context, req *entity.GetCategoryIdRequest) (*entity.GetcategoryIdResponse, error)
 	DeletCategory(ctx context.Context, req *entity.DeleteCategoryRequest) error
+	GetVariants(ctx context.Context, req *entity.GetVariantsRequest) (*entity.GetVariantsResponse, error)
+	CreateVariant(ctx context.Context, req *entity.CreateVariantRequest) (*entity.CreateVariantResponse, error)
+	UpdateVariant(ctx context.Context, req *entity.UpdateVariantRequest) (*entity.UpdateVariantResponse, error)
+	DeleteVariant(ctx context.Context, req *entity.DeleteVariantRequest) error
+	GetProductImages(ctx context.Context, req *entity.GetProductImagesRequest) (*entity.GetProductImagesResponse, error)
+	AddProductImages(ctx context.Context, req *entity.AddProductImagesRequest) (*entity.GetProductImagesResponse, error)
+	ReorderProductImages(ctx context.Context, req *entity.ReorderProductImagesRequest) (*entity.GetProductImagesResponse, error)
+	SetPrimaryProductImage(ctx context.Context, req *entity.SetPrimaryProductImageRequest) (*entity.GetProductImagesResponse, error)
+	DeleteProductImage(ctx context.Context, req *entity.DeleteProductImageRequest) error
+	GetCategoryTree(ctx context.Context, req *entity.GetCategoryTreeRequest) (*entity.GetCategoryTreeResponse, error)
+	MoveCategory(ctx context.Context, req *entity.MoveCategoryRequest) (*entity.MoveCategoryResponse, error)
+	UpdateCategory(ctx context.Context, req *entity.UpdateCategoryRequest) (*entity.UpdateCategoryResponse, error)
+	GetReviews(ctx context.Context, req *entity.GetReviewsRequest) (*entity.GetReviewsResponse, error)
+	CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.CreateReviewResponse, error)
+	UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) (*entity.UpdateReviewResponse, error)
+	DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error
+	ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) (*entity.ReplyReviewResponse, error)
+	ImportProducts(ctx context.Context, req *entity.ImportProductsRequest, rows []entity.ImportProductRow) ([]entity.ImportRowResult, error)
+	CheckShopOwner(ctx context.Context, shopId, userId string) error
+	ExportProducts(ctx context.Context, req *entity.ExportProductsRequest, fn func(*entity.ExportProductItem) error) error
+	UpdateProductStatus(ctx context.Context, req *entity.UpdateProductStatusRequest) (*entity.UpdateProductStatusResponse, error)
+	GetTrash(ctx context.Context, req *entity.GetTrashRequest) (*entity.GetTrashResponse, error)
+	RestoreTrash(ctx context.Context, req *entity.RestoreTrashRequest) (*entity.RestoreTrashResponse, error)
+	PurgeTrash(ctx context.Context, req *entity.PurgeTrashRequest) (*entity.PurgeTrashResponse, error)
+	GetProductRevisions(ctx context.Context, req *entity.GetProductRevisionsRequest) (*entity.GetProductRevisionsResponse, error)
+	RollbackProduct(ctx context.Context, req *entity.RollbackProductRequest) (*entity.RollbackProductResponse, error)
+	GetShopBySlug(ctx context.Context, req *entity.GetShopBySlugRequest) (*entity.GetShopBySlugResponse, error)
+	GetProductBySlug(ctx context.Context, req *entity.GetProductBySlugRequest) (*entity.GetProductBySlugResponse, error)
+	GetCategoryAttributes(ctx context.Context, req *entity.GetCategoryAttributesRequest) (*entity.GetCategoryAttributesResponse, error)
+	CreateCategoryAttribute(ctx context.Context, req *entity.CreateCategoryAttributeRequest) (*entity.CreateCategoryAttributeResponse, error)
+	UpdateCategoryAttribute(ctx context.Context, req *entity.UpdateCategoryAttributeRequest) (*entity.UpdateCategoryAttributeResponse, error)
+	DeleteCategoryAttribute(ctx context.Context, req *entity.DeleteCategoryAttributeRequest) error
+	GetProductAttributeDefinitions(ctx context.Context, productId, userId string) ([]entity.CategoryAttribute, error)
+	SetProductAttributes(ctx context.Context, req *entity.SetProductAttributesRequest, values []entity.ProductAttribute) (*entity.SetProductAttributesResponse, error)
+	GetBrands(ctx context.Context, req *entity.GetBrandsRequest) (*entity.GetBrandsResponse, error)
+	GetUnbrandedMerks(ctx context.Context) ([]entity.MerkCount, error)
+	LinkBrands(ctx context.Context, groups []entity.BrandGroup) (int, error)
+	GetBrand(ctx context.Context, req *entity.GetBrandRequest) (*entity.BrandDetail, error)
+	UpdateBrand(ctx context.Context, req *entity.UpdateBrandRequest) (*entity.BrandDetail, error)
+	AddBrandAlias(ctx context.Context, req *entity.AddBrandAliasRequest) (*entity.BrandDetail, error)
+	DeleteBrandAlias(ctx context.Context, req *entity.DeleteBrandAliasRequest) (*entity.BrandDetail, error)
+	GetRelatedTarget(ctx context.Context, req *entity.GetRelatedProductsRequest) (*entity.RelatedCandidate, error)
+	GetRelatedCandidates(ctx context.Context, target *entity.RelatedCandidate) ([]entity.RelatedCandidate, error)
+	GetTags(ctx context.Context, req *entity.GetTagsRequest) (*entity.GetTagsResponse, error)
+	SetBundleComponents(ctx context.Context, req *entity.SetBundleComponentsRequest) (*entity.SetBundleComponentsResponse, error)
+	SetProductSale(ctx context.Context, req *entity.SetProductSaleRequest) (*entity.ProductSaleResponse, error)
+	DeleteProductSale(ctx context.Context, req *entity.DeleteProductSaleRequest) (*entity.ProductSaleResponse, error)
+	GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (*entity.GetPriceHistoryResponse, error)
+	SetPriceTiers(ctx context.Context, req *entity.SetPriceTiersRequest) (*entity.SetPriceTiersResponse, error)
+	GetPricedProduct(ctx context.Context, req *entity.GetPriceQuoteRequest) (*entity.PricedProduct, error)
+	SetExchangeRates(ctx context.Context, req *entity.SetExchangeRatesRequest) error
+	GetExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error)
 }
 
 type ShopService interface {
@@ -37,4 +89,52 @@ type ShopService interface {
 	GetCategory(ctx context.Context, req *entity.GetCategoryRequest) (*entity.GetCategoryResponse, error)
 	GetCategoryId(ctx context.Context, req *entity.GetCategoryIdRequest) (*entity.GetcategoryIdResponse, error)
 	DeletCategory(ctx context.Context, req *entity.DeleteCategoryRequest) error
+	GetVariants(ctx context.Context, req *entity.GetVariantsRequest) (*entity.GetVariantsResponse, error)
+	CreateVariant(ctx context.Context, req *entity.CreateVariantRequest) (*entity.CreateVariantResponse, error)
+	UpdateVariant(ctx context.Context, req *entity.UpdateVariantRequest) (*entity.UpdateVariantResponse, error)
+	DeleteVariant(ctx context.Context, req *entity.DeleteVariantRequest) error
+	GetProductImages(ctx context.Context, req *entity.GetProductImagesRequest) (*entity.GetProductImagesResponse, error)
+	AddProductImages(ctx context.Context, req *entity.AddProductImagesRequest) (*entity.GetProductImagesResponse, error)
+	ReorderProductImages(ctx context.Context, req *entity.ReorderProductImagesRequest) (*entity.GetProductImagesResponse, error)
+	SetPrimaryProductImage(ctx context.Context, req *entity.SetPrimaryProductImageRequest) (*entity.GetProductImagesResponse, error)
+	DeleteProductImage(ctx context.Context, req *entity.DeleteProductImageRequest) error
+	GetCategoryTree(ctx context.Context, req *entity.GetCategoryTreeRequest) (*entity.GetCategoryTreeResponse, error)
+	MoveCategory(ctx context.Context, req *entity.MoveCategoryRequest) (*entity.MoveCategoryResponse, error)
+	UpdateCategory(ctx context.Context, req *entity.UpdateCategoryRequest) (*entity.UpdateCategoryResponse, error)
+	GetReviews(ctx context.Context, req *entity.GetReviewsRequest) (*entity.GetReviewsResponse, error)
+	CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.CreateReviewResponse, error)
+	UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) (*entity.UpdateReviewResponse, error)
+	DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error
+	ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) (*entity.ReplyReviewResponse, error)
+	ImportProducts(ctx context.Context, req *entity.ImportProductsRequest) (*entity.ImportProductsResponse, error)
+	ExportProducts(ctx context.Context, req *entity.ExportProductsRequest) (entity.ExportWriter, error)
+	UpdateProductStatus(ctx context.Context, req *entity.UpdateProductStatusRequest) (*entity.UpdateProductStatusResponse, error)
+	GetTrash(ctx context.Context, req *entity.GetTrashRequest) (*entity.GetTrashResponse, error)
+	RestoreTrash(ctx context.Context, req *entity.RestoreTrashRequest) (*entity.RestoreTrashResponse, error)
+	PurgeTrash(ctx context.Context, req *entity.PurgeTrashRequest) (*entity.PurgeTrashResponse, error)
+	GetProductRevisions(ctx context.Context, req *entity.GetProductRevisionsRequest) (*entity.GetProductRevisionsResponse, error)
+	RollbackProduct(ctx context.Context, req *entity.RollbackProductRequest) (*entity.RollbackProductResponse, error)
+	GetShopBySlug(ctx context.Context, req *entity.GetShopBySlugRequest) (*entity.GetShopBySlugResponse, error)
+	GetProductBySlug(ctx context.Context, req *entity.GetProductBySlugRequest) (*entity.GetProductBySlugResponse, error)
+	GetCategoryAttributes(ctx context.Context, req *entity.GetCategoryAttributesRequest) (*entity.GetCategoryAttributesResponse, error)
+	CreateCategoryAttribute(ctx context.Context, req *entity.CreateCategoryAttributeRequest) (*entity.CreateCategoryAttributeResponse, error)
+	UpdateCategoryAttribute(ctx context.Context, req *entity.UpdateCategoryAttributeRequest) (*entity.UpdateCategoryAttributeResponse, error)
+	DeleteCategoryAttribute(ctx context.Context, req *entity.DeleteCategoryAttributeRequest) error
+	SetProductAttributes(ctx context.Context, req *entity.SetProductAttributesRequest) (*entity.SetProductAttributesResponse, error)
+	GetBrands(ctx context.Context, req *entity.GetBrandsRequest) (*entity.GetBrandsResponse, error)
+	NormalizeBrands(ctx context.Context, req *entity.NormalizeBrandsRequest) (*entity.NormalizeBrandsResponse, error)
+	GetBrand(ctx context.Context, req *entity.GetBrandRequest) (*entity.BrandDetail, error)
+	UpdateBrand(ctx context.Context, req *entity.UpdateBrandRequest) (*entity.BrandDetail, error)
+	AddBrandAlias(ctx context.Context, req *entity.AddBrandAliasRequest) (*entity.BrandDetail, error)
+	DeleteBrandAlias(ctx context.Context, req *entity.DeleteBrandAliasRequest) (*entity.BrandDetail, error)
+	GetRelatedProducts(ctx context.Context, req *entity.GetRelatedProductsRequest) (*entity.GetRelatedProductsResponse, error)
+	GetTags(ctx context.Context, req *entity.GetTagsRequest) (*entity.GetTagsResponse, error)
+	SetBundleComponents(ctx context.Context, req *entity.SetBundleComponentsRequest) (*entity.SetBundleComponentsResponse, error)
+	SetProductSale(ctx context.Context, req *entity.SetProductSaleRequest) (*entity.ProductSaleResponse, error)
+	DeleteProductSale(ctx context.Context, req *entity.DeleteProductSaleRequest) (*entity.ProductSaleResponse, error)
+	GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (*entity.GetPriceHistoryResponse, error)
+	SetPriceTiers(ctx context.Context, req *entity.SetPriceTiersRequest) (*entity.SetPriceTiersResponse, error)
+	GetPriceQuote(ctx context.Context, req *entity.GetPriceQuoteRequest) (*entity.GetPriceQuoteResponse, error)
+	SetExchangeRates(ctx context.Context, req *entity.SetExchangeRatesRequest) (*entity.GetExchangeRatesResponse, error)
+	GetExchangeRates(ctx context.Context) (*entity.GetExchangeRatesResponse, error)
 }
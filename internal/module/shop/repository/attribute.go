@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// effectiveAttributes defines the attrs CTE, the attributes of a category and
+// of its ancestors where the nearest definition of a code wins. anchor is the
+// sql expression of the category id, it is followed by maxCategoryDepth in
+// the arguments.
+func effectiveAttributes(anchor string) string {
+	return `
+		WITH RECURSIVE path AS (
+			SELECT id, parent_id, 0 AS depth FROM categories WHERE id = ` + anchor + `
+			UNION ALL
+			SELECT c.id, c.parent_id, path.depth + 1 FROM categories c
+			JOIN path ON c.id = path.parent_id
+			WHERE path.depth < ?
+		), attrs AS (
+			SELECT DISTINCT ON (ca.code) ca.id, ca.category_id, ca.code, ca.name, ca.type, ca.allowed_values, ca.unit
+			FROM category_attributes ca
+			JOIN path ON path.id = ca.category_id
+			ORDER BY ca.code, path.depth
+		)
+	`
+}
+
+type categoryAttributeDao struct {
+	entity.CategoryAttribute
+	AllowedValues pq.StringArray `db:"allowed_values"`
+}
+
+func (d categoryAttributeDao) toEntity() entity.CategoryAttribute {
+	attr := d.CategoryAttribute
+	attr.AllowedValues = []string(d.AllowedValues)
+	return attr
+}
+
+func (r *shopRepository) GetCategoryAttributes(ctx context.Context, req *entity.GetCategoryAttributesRequest) (*entity.GetCategoryAttributesResponse, error) {
+	var (
+		resp = new(entity.GetCategoryAttributesResponse)
+		err  error
+	)
+
+	if err := r.checkCategoryOwner(ctx, r.db, req.CategoryId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	resp.Attributes, err = r.getCategoryAttributes(ctx, r.db, req.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) CreateCategoryAttribute(ctx context.Context, req *entity.CreateCategoryAttributeRequest) (*entity.CreateCategoryAttributeResponse, error) {
+	var (
+		resp = new(entity.CreateCategoryAttributeResponse)
+		data categoryAttributeDao
+	)
+
+	if err := r.checkCategoryOwner(ctx, r.db, req.CategoryId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	values := req.AllowedValues
+	if values == nil {
+		values = make([]string, 0)
+	}
+
+	query := `
+		INSERT INTO category_attributes (category_id, code, name, type, allowed_values, unit)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id, category_id, code, name, type, allowed_values, unit
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
+		req.CategoryId,
+		req.Code,
+		req.Name,
+		req.Type,
+		pq.Array(values),
+		req.Unit,
+	).StructScan(&data)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			return nil, errmsg.NewCustomErrors(409,
+				errmsg.WithMessage("Atribut sudah ada"),
+				errmsg.WithErrors("code", "kode atribut sudah digunakan pada kategori ini."),
+			)
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateCategoryAttribute - Failed to create attribute")
+		return nil, err
+	}
+
+	resp.CategoryAttribute = data.toEntity()
+
+	return resp, nil
+}
+
+func (r *shopRepository) UpdateCategoryAttribute(ctx context.Context, req *entity.UpdateCategoryAttributeRequest) (*entity.UpdateCategoryAttributeResponse, error) {
+	var (
+		resp     = new(entity.UpdateCategoryAttributeResponse)
+		data     categoryAttributeDao
+		attrType string
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::UpdateCategoryAttribute - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.GetContext(ctx, &attrType, tx.Rebind(`
+		SELECT ca.type FROM category_attributes ca
+		JOIN categories c ON c.id = ca.category_id
+		WHERE ca.id = ? AND ca.category_id = ? AND c.user_id = ? AND c.deleted_at IS NULL
+		FOR UPDATE OF ca
+	`), req.Id, req.CategoryId, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Atribut tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateCategoryAttribute - Failed to get attribute")
+		return nil, err
+	}
+
+	values := req.AllowedValues
+	if values == nil {
+		values = make([]string, 0)
+	}
+
+	switch {
+	case attrType != entity.AttributeTypeEnum && len(values) > 0:
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Atribut tidak valid"),
+			errmsg.WithErrors("allowedValues", "hanya untuk atribut bertipe enum."),
+		)
+	case attrType == entity.AttributeTypeEnum && len(values) == 0:
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Atribut tidak valid"),
+			errmsg.WithErrors("allowedValues", "wajib diisi untuk atribut bertipe enum."),
+		)
+	}
+
+	if attrType == entity.AttributeTypeEnum {
+		// a value can only be dropped once no product uses it anymore
+		inUse := make([]string, 0)
+		err = tx.SelectContext(ctx, &inUse, tx.Rebind(`
+			SELECT DISTINCT value FROM product_attributes
+			WHERE attribute_id = ? AND value <> ALL(?::text[])
+			ORDER BY value
+		`), req.Id, pq.Array(values))
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::UpdateCategoryAttribute - Failed to check values in use")
+			return nil, err
+		}
+
+		if len(inUse) > 0 {
+			opts := []errmsg.Option{errmsg.WithMessage("Nilai atribut masih digunakan oleh produk")}
+			for _, v := range inUse {
+				opts = append(opts, errmsg.WithErrors("allowedValues", "nilai "+v+" masih digunakan."))
+			}
+			return nil, errmsg.NewCustomErrors(409, opts...)
+		}
+	}
+
+	query := `
+		UPDATE category_attributes
+		SET name = ?, allowed_values = ?, unit = ?, updated_at = NOW()
+		WHERE id = ?
+		RETURNING id, category_id, code, name, type, allowed_values, unit
+	`
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.Name, pq.Array(values), req.Unit, req.Id).StructScan(&data)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateCategoryAttribute - Failed to update attribute")
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateCategoryAttribute - Failed to commit transaction")
+		return nil, err
+	}
+
+	resp.CategoryAttribute = data.toEntity()
+
+	return resp, nil
+}
+
+// DeleteCategoryAttribute removes the definition for good, the values stored
+// for the products go with it.
+func (r *shopRepository) DeleteCategoryAttribute(ctx context.Context, req *entity.DeleteCategoryAttributeRequest) error {
+	query := `
+		DELETE FROM category_attributes ca
+		USING categories c
+		WHERE ca.id = ? AND ca.category_id = ?
+			AND c.id = ca.category_id AND c.user_id = ? AND c.deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.CategoryId, req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteCategoryAttribute - Failed to delete attribute")
+		return err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Atribut tidak ditemukan"))
+	}
+
+	return nil
+}
+
+// GetProductAttributeDefinitions returns the attributes available to the
+// product of the owner through its category.
+func (r *shopRepository) GetProductAttributeDefinitions(ctx context.Context, productId, userId string) ([]entity.CategoryAttribute, error) {
+	var categoryId string
+
+	err := r.db.GetContext(ctx, &categoryId, r.db.Rebind(`
+		SELECT category_id FROM products WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+	`), productId, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::GetProductAttributeDefinitions - Failed to get product")
+		return nil, err
+	}
+
+	return r.getCategoryAttributes(ctx, r.db, categoryId)
+}
+
+// SetProductAttributes replaces the attribute values of the product with
+// values, which have been checked against the definitions already.
+func (r *shopRepository) SetProductAttributes(ctx context.Context, req *entity.SetProductAttributesRequest, values []entity.ProductAttribute) (*entity.SetProductAttributesResponse, error) {
+	var (
+		resp         = new(entity.SetProductAttributesResponse)
+		attributeIds = make([]string, 0, len(values))
+		attrValues   = make([]string, 0, len(values))
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::SetProductAttributes - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := r.checkProductOwner(ctx, tx, req.ProductId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		DELETE FROM product_attributes WHERE product_id = ?
+	`), req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetProductAttributes - Failed to delete values")
+		return nil, err
+	}
+
+	for _, v := range values {
+		attributeIds = append(attributeIds, v.AttributeId)
+		attrValues = append(attrValues, v.Value)
+	}
+
+	if len(values) > 0 {
+		_, err = tx.ExecContext(ctx, tx.Rebind(`
+			INSERT INTO product_attributes (product_id, attribute_id, value)
+			SELECT ?, UNNEST(?::uuid[]), UNNEST(?::text[])
+		`), req.ProductId, pq.Array(attributeIds), pq.Array(attrValues))
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::SetProductAttributes - Failed to insert values")
+			return nil, err
+		}
+	}
+
+	resp.Attributes, err = r.getProductAttributes(ctx, tx, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetProductAttributes - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// checkCategoryOwner makes sure the category exists, is not deleted and belongs to the user.
+func (r *shopRepository) checkCategoryOwner(ctx context.Context, q sqlx.QueryerContext, categoryId, userId string) error {
+	var exists bool
+
+	err := sqlx.GetContext(ctx, q, &exists, r.db.Rebind(`
+		SELECT EXISTS (
+			SELECT 1 FROM categories
+			WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		)
+	`), categoryId, userId)
+	if err != nil {
+		log.Error().Err(err).Str("category_id", categoryId).Msg("repository::checkCategoryOwner - Failed to check category")
+		return err
+	}
+	if !exists {
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Kategori tidak ditemukan"))
+	}
+
+	return nil
+}
+
+// getCategoryAttributes returns the effective attributes of the category.
+func (r *shopRepository) getCategoryAttributes(ctx context.Context, q sqlx.QueryerContext, categoryId string) ([]entity.CategoryAttribute, error) {
+	var (
+		data  = make([]categoryAttributeDao, 0)
+		attrs = make([]entity.CategoryAttribute, 0)
+	)
+
+	query := effectiveAttributes("?") + `
+		SELECT id, category_id, code, name, type, allowed_values, unit
+		FROM attrs
+		ORDER BY name, code
+	`
+
+	err := sqlx.SelectContext(ctx, q, &data, r.db.Rebind(query), categoryId, maxCategoryDepth)
+	if err != nil {
+		log.Error().Err(err).Str("category_id", categoryId).Msg("repository::getCategoryAttributes - Failed to get attributes")
+		return nil, err
+	}
+
+	for _, d := range data {
+		attrs = append(attrs, d.toEntity())
+	}
+
+	return attrs, nil
+}
+
+// getProductAttributes returns the values of the attributes the product
+// currently has through its category.
+func (r *shopRepository) getProductAttributes(ctx context.Context, q sqlx.QueryerContext, productId string) ([]entity.ProductAttribute, error) {
+	var attrs = make([]entity.ProductAttribute, 0)
+
+	query := effectiveAttributes("(SELECT category_id FROM products WHERE id = ?)") + `
+		SELECT attrs.id as attribute_id, attrs.code, attrs.name, attrs.unit, pa.value
+		FROM attrs
+		JOIN product_attributes pa ON pa.attribute_id = attrs.id
+		WHERE pa.product_id = ?
+		ORDER BY attrs.name, attrs.code
+	`
+
+	err := sqlx.SelectContext(ctx, q, &attrs, r.db.Rebind(query), productId, maxCategoryDepth, productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::getProductAttributes - Failed to get attributes")
+		return nil, err
+	}
+
+	return attrs, nil
+}
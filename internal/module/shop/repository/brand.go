@@ -0,0 +1,464 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+func (r *shopRepository) GetBrands(ctx context.Context, req *entity.GetBrandsRequest) (*entity.GetBrandsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.BrandItem
+	}
+
+	var (
+		resp = new(entity.GetBrandsResponse)
+		data = make([]dao, 0, req.Paginate)
+		args = make([]any, 0)
+	)
+	resp.Items = make([]entity.BrandItem, 0, req.Paginate)
+
+	query := `
+		SELECT
+			COUNT(b.id) OVER() as total_data,
+			b.id,
+			b.name,
+			b.logo_url,
+			(
+				SELECT COUNT(*) FROM products p
+				WHERE p.brand_id = b.id AND p.deleted_at IS NULL AND ` + productVisible + `
+			) as product_count
+		FROM brands b
+		WHERE true
+	`
+
+	// a keyword also finds a brand by any of its aliases
+	if key := entity.BrandKey(req.Keyword); key != "" {
+		query += ` AND (
+			b.name ILIKE ?
+			OR EXISTS (SELECT 1 FROM brand_aliases a WHERE a.brand_id = b.id AND a.alias LIKE ?)
+		)`
+		args = append(args, "%"+strings.TrimSpace(req.Keyword)+"%", "%"+key+"%")
+	}
+
+	query += " ORDER BY b.name, b.id LIMIT ? OFFSET ?"
+	args = append(args, req.Paginate, req.Paginate*(req.Page-1))
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetBrands - Failed to get brands")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.BrandItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *shopRepository) GetBrand(ctx context.Context, req *entity.GetBrandRequest) (*entity.BrandDetail, error) {
+	return getBrand(ctx, r.db, req.Id)
+}
+
+// getBrand returns a brand with its aliases.
+func getBrand(ctx context.Context, q sqlx.ExtContext, id string) (*entity.BrandDetail, error) {
+	var data = new(entity.BrandDetail)
+
+	err := sqlx.GetContext(ctx, q, data, q.Rebind(`
+		SELECT id, name, logo_url FROM brands WHERE id = ?
+	`), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Merek tidak ditemukan"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository::getBrand - Failed to get brand")
+		return nil, err
+	}
+
+	data.Aliases = make([]string, 0)
+	err = sqlx.SelectContext(ctx, q, &data.Aliases, q.Rebind(`
+		SELECT alias FROM brand_aliases WHERE brand_id = ? ORDER BY alias
+	`), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", id).Msg("repository::getBrand - Failed to get aliases")
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// lockBrand locks a brand for the rest of the transaction and returns its
+// name.
+func lockBrand(ctx context.Context, tx *sqlx.Tx, id string) (string, error) {
+	var name string
+
+	err := tx.GetContext(ctx, &name, tx.Rebind(`
+		SELECT name FROM brands WHERE id = ? FOR UPDATE
+	`), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errmsg.NewCustomErrors(404, errmsg.WithMessage("Merek tidak ditemukan"))
+		}
+		log.Error().Err(err).Str("id", id).Msg("repository::lockBrand - Failed to get brand")
+		return "", err
+	}
+
+	return name, nil
+}
+
+// UpdateBrand renames a brand and sets its logo. The alias of the new name is
+// added and the merk of the brand products follows the name. A name already
+// known as another brand is refused, that brand is merged by adding the name
+// as an alias instead.
+func (r *shopRepository) UpdateBrand(ctx context.Context, req *entity.UpdateBrandRequest) (*entity.BrandDetail, error) {
+	var key = entity.BrandKey(req.Name)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::UpdateBrand - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockBrand(ctx, tx, req.Id); err != nil {
+		return nil, err
+	}
+
+	conflict := errmsg.NewCustomErrors(409,
+		errmsg.WithMessage("Merek sudah ada"),
+		errmsg.WithErrors("name", "nama sudah digunakan merek lain, tambahkan sebagai alias untuk menggabungkan merek."),
+	)
+
+	var taken bool
+	err = tx.GetContext(ctx, &taken, tx.Rebind(`
+		SELECT EXISTS (SELECT 1 FROM brand_aliases WHERE alias = ? AND brand_id <> ?)
+	`), key, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateBrand - Failed to check alias")
+		return nil, err
+	}
+	if taken {
+		return nil, conflict
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		UPDATE brands SET name = ?, logo_url = NULLIF(?, ''), updated_at = NOW() WHERE id = ?
+	`), req.Name, req.LogoURL, req.Id)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			return nil, conflict
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateBrand - Failed to update brand")
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		INSERT INTO brand_aliases (alias, brand_id) VALUES (?, ?)
+		ON CONFLICT (alias) DO NOTHING
+	`), key, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateBrand - Failed to create alias")
+		return nil, err
+	}
+
+	if err := moveBrandProducts(ctx, tx, req.Id, req.Id, req.Name, req.UserId); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateBrand - Failed to rename products")
+		return nil, err
+	}
+
+	resp, err := getBrand(ctx, tx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("repository::UpdateBrand - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// AddBrandAlias makes an alias resolve to the brand. An alias of another brand
+// is moved over, when it is the alias of that brand name the whole brand is
+// merged: its aliases and products move over and it is deleted.
+func (r *shopRepository) AddBrandAlias(ctx context.Context, req *entity.AddBrandAliasRequest) (*entity.BrandDetail, error) {
+	var owner struct {
+		Id   string `db:"id"`
+		Name string `db:"name"`
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::AddBrandAlias - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	name, err := lockBrand(ctx, tx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.GetContext(ctx, &owner, tx.Rebind(`
+		SELECT b.id, b.name
+		FROM brand_aliases a
+		JOIN brands b ON b.id = a.brand_id
+		WHERE a.alias = ?
+		FOR UPDATE OF b
+	`), req.Alias)
+	switch {
+	case err == sql.ErrNoRows:
+		_, err = tx.ExecContext(ctx, tx.Rebind(`
+			INSERT INTO brand_aliases (alias, brand_id) VALUES (?, ?)
+		`), req.Alias, req.Id)
+	case err != nil:
+	case owner.Id == req.Id:
+	case entity.BrandKey(owner.Name) == req.Alias:
+		err = mergeBrand(ctx, tx, owner.Id, req.Id, name, req.UserId)
+	default:
+		_, err = tx.ExecContext(ctx, tx.Rebind(`
+			UPDATE brand_aliases SET brand_id = ? WHERE alias = ?
+		`), req.Id, req.Alias)
+	}
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::AddBrandAlias - Failed to add alias")
+		return nil, err
+	}
+
+	resp, err := getBrand(ctx, tx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("repository::AddBrandAlias - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// mergeBrand moves the aliases and the products of a brand to another one,
+// named name, and deletes it.
+func mergeBrand(ctx context.Context, tx *sqlx.Tx, fromId, toId, name, userId string) error {
+	_, err := tx.ExecContext(ctx, tx.Rebind(`
+		UPDATE brand_aliases SET brand_id = ? WHERE brand_id = ?
+	`), toId, fromId)
+	if err != nil {
+		return err
+	}
+
+	if err := moveBrandProducts(ctx, tx, fromId, toId, name, userId); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		DELETE FROM brands WHERE id = ?
+	`), fromId)
+	return err
+}
+
+// moveBrandProducts links the products of brand fromId to brand toId, which
+// may be the same, and sets their merk to name. The products whose merk
+// changes get a revision by the admin, like any other edit of the merk.
+func moveBrandProducts(ctx context.Context, tx *sqlx.Tx, fromId, toId, name, userId string) error {
+	_, err := tx.ExecContext(ctx, tx.Rebind(`
+		INSERT INTO product_revisions (product_id, user_id, action, before, after)
+		SELECT p.id, ?, ?, `+productSnapshot+`, `+productSnapshot+` || jsonb_build_object('merk', ?::text)
+		FROM products p
+		WHERE p.brand_id = ? AND p.merk IS DISTINCT FROM ?
+	`), userId, entity.RevisionActionBrand, name, fromId, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		UPDATE products
+		SET brand_id = ?, merk = ?, updated_at = NOW()
+		WHERE brand_id = ? AND (brand_id <> ? OR merk IS DISTINCT FROM ?)
+	`), toId, name, fromId, toId, name)
+	return err
+}
+
+// DeleteBrandAlias removes an alias, the merk it spells will create a new
+// brand again. The alias of the brand name is kept.
+func (r *shopRepository) DeleteBrandAlias(ctx context.Context, req *entity.DeleteBrandAliasRequest) (*entity.BrandDetail, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::DeleteBrandAlias - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	name, err := lockBrand(ctx, tx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.BrandKey(name) == req.Alias {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Alias tidak dapat dihapus"),
+			errmsg.WithErrors("alias", "alias nama merek tidak dapat dihapus."),
+		)
+	}
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(`
+		DELETE FROM brand_aliases WHERE alias = ? AND brand_id = ?
+	`), req.Alias, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteBrandAlias - Failed to delete alias")
+		return nil, err
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Alias tidak ditemukan"))
+	}
+
+	resp, err := getBrand(ctx, tx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("repository::DeleteBrandAlias - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetUnbrandedMerks counts the products per merk that are not linked to a
+// brand yet, deleted products included.
+func (r *shopRepository) GetUnbrandedMerks(ctx context.Context) ([]entity.MerkCount, error) {
+	var merks = make([]entity.MerkCount, 0)
+
+	err := r.db.SelectContext(ctx, &merks, `
+		SELECT merk, COUNT(*) as products
+		FROM products
+		WHERE brand_id IS NULL
+		GROUP BY merk
+		ORDER BY merk
+	`)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::GetUnbrandedMerks - Failed to get merks")
+		return nil, err
+	}
+
+	return merks, nil
+}
+
+// LinkBrands links the products of every group to its brand, creating the
+// brand when none is known by the group key yet. The merk of the products is
+// set to the brand name. It returns the number of products linked.
+func (r *shopRepository) LinkBrands(ctx context.Context, groups []entity.BrandGroup) (int, error) {
+	var linked int
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::LinkBrands - Failed to begin transaction")
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, group := range groups {
+		brandId, name, err := resolveBrand(ctx, tx, group.Name)
+		if err != nil {
+			return 0, err
+		}
+		if brandId == nil {
+			continue
+		}
+
+		result, err := tx.ExecContext(ctx, tx.Rebind(`
+			UPDATE products
+			SET brand_id = ?, merk = ?
+			WHERE brand_id IS NULL AND merk = ANY(?::text[])
+		`), *brandId, name, pq.Array(group.Merks))
+		if err != nil {
+			log.Error().Err(err).Str("brand", group.Name).Msg("repository::LinkBrands - Failed to link products")
+			return 0, err
+		}
+
+		affected, _ := result.RowsAffected()
+		linked += int(affected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("repository::LinkBrands - Failed to commit transaction")
+		return 0, err
+	}
+
+	return linked, nil
+}
+
+// resolveBrand returns the brand a merk refers to and its canonical name,
+// creating the brand on first use. An empty merk has no brand.
+//
+// Any merk a seller types thus becomes a brand, so the catalog grows without
+// waiting on an admin. Misspelled or duplicate brands are fixed afterwards
+// through the admin brand endpoints: renaming, or adding the spelling as an
+// alias of the right brand, which merges them.
+func resolveBrand(ctx context.Context, tx *sqlx.Tx, merk string) (*string, string, error) {
+	var (
+		key   = entity.BrandKey(merk)
+		brand struct {
+			Id   string `db:"id"`
+			Name string `db:"name"`
+		}
+	)
+
+	if key == "" {
+		return nil, merk, nil
+	}
+
+	err := tx.GetContext(ctx, &brand, tx.Rebind(`
+		SELECT b.id, b.name
+		FROM brand_aliases a
+		JOIN brands b ON b.id = a.brand_id
+		WHERE a.alias = ?
+	`), key)
+	if err == nil {
+		return &brand.Id, brand.Name, nil
+	}
+	if err != sql.ErrNoRows {
+		log.Error().Err(err).Str("merk", merk).Msg("repository::resolveBrand - Failed to get brand")
+		return nil, "", err
+	}
+
+	// the name may already belong to a brand under another alias
+	err = tx.GetContext(ctx, &brand, tx.Rebind(`
+		INSERT INTO brands (name) VALUES (?)
+		ON CONFLICT ((LOWER(name))) DO UPDATE SET updated_at = brands.updated_at
+		RETURNING id, name
+	`), entity.BrandName(merk))
+	if err != nil {
+		log.Error().Err(err).Str("merk", merk).Msg("repository::resolveBrand - Failed to create brand")
+		return nil, "", err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		INSERT INTO brand_aliases (alias, brand_id) VALUES (?, ?)
+		ON CONFLICT (alias) DO NOTHING
+	`), key, brand.Id)
+	if err != nil {
+		log.Error().Err(err).Str("merk", merk).Msg("repository::resolveBrand - Failed to create alias")
+		return nil, "", err
+	}
+
+	return &brand.Id, brand.Name, nil
+}
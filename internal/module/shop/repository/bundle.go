@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// componentVisible is the condition for the bundle component cp to be sold,
+// the same as productVisible for a product that is not deleted.
+const componentVisible = "cp.deleted_at IS NULL AND cp.status = 'published' AND (cp.publish_at IS NULL OR cp.publish_at <= NOW())"
+
+// componentStock is what a bundle component has available, it expects the
+// component to be aliased as cp. A deleted or unpublished component has
+// nothing left.
+const componentStock = `CASE WHEN ` + componentVisible + ` THEN COALESCE((
+	SELECT SUM(v.stock) FROM product_variants v
+	WHERE v.product_id = cp.id AND v.deleted_at IS NULL
+), cp.stock) ELSE 0 END`
+
+// bundleStock is the number of bundles the components of p are enough for,
+// it is NULL for a product that is not a bundle.
+const bundleStock = `(
+	SELECT MIN((` + componentStock + `) / bi.quantity)
+	FROM product_bundle_items bi
+	JOIN products cp ON cp.id = bi.product_id
+	WHERE bi.bundle_id = p.id
+)`
+
+func (r *shopRepository) SetBundleComponents(ctx context.Context, req *entity.SetBundleComponentsRequest) (*entity.SetBundleComponentsResponse, error) {
+	var (
+		resp   = new(entity.SetBundleComponentsResponse)
+		bundle struct {
+			ShopId string `db:"shop_id"`
+			Type   string `db:"type"`
+		}
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::SetBundleComponents - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.GetContext(ctx, &bundle, tx.Rebind(`
+		SELECT shop_id, type FROM products
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		FOR UPDATE
+	`), req.ProductId, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetBundleComponents - Failed to get product")
+		return nil, err
+	}
+
+	if bundle.Type != entity.ProductTypeBundle {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Produk bukan bundel"),
+			errmsg.WithErrors("components", "hanya produk bundel yang memiliki komponen."),
+		)
+	}
+
+	if err := setBundleComponents(ctx, tx, req.ProductId, bundle.ShopId, req.Components); err != nil {
+		return nil, err
+	}
+
+	resp.Components, err = getBundleComponents(ctx, tx, req.ProductId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.GetContext(ctx, &resp.TotalStock, tx.Rebind(`
+		SELECT COALESCE(`+bundleStock+`, 0) FROM products p WHERE p.id = ?
+	`), req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetBundleComponents - Failed to get stock")
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetBundleComponents - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// setBundleComponents replaces the components of a bundle. Components must be
+// single products of the same shop that are not deleted.
+func setBundleComponents(ctx context.Context, tx *sqlx.Tx, bundleId, shopId string, components []entity.BundleComponentRequest) error {
+	var (
+		ids        = make([]string, len(components))
+		quantities = make([]int64, len(components))
+		valid      int
+	)
+
+	for i, c := range components {
+		ids[i] = c.ProductId
+		quantities[i] = int64(c.Quantity)
+	}
+
+	err := tx.GetContext(ctx, &valid, tx.Rebind(`
+		SELECT COUNT(*) FROM products
+		WHERE id = ANY(?::uuid[])
+			AND id <> ?
+			AND shop_id = ?
+			AND type = 'single'
+			AND deleted_at IS NULL
+	`), pq.Array(ids), bundleId, shopId)
+	if err != nil {
+		log.Error().Err(err).Str("bundle_id", bundleId).Msg("repository::setBundleComponents - Failed to check components")
+		return err
+	}
+
+	if valid != len(ids) {
+		return errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Komponen bundel tidak valid"),
+			errmsg.WithErrors("components", "komponen harus produk non-bundel dari toko yang sama."),
+		)
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		DELETE FROM product_bundle_items WHERE bundle_id = ?
+	`), bundleId)
+	if err != nil {
+		log.Error().Err(err).Str("bundle_id", bundleId).Msg("repository::setBundleComponents - Failed to delete components")
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		INSERT INTO product_bundle_items (bundle_id, product_id, quantity, position)
+		SELECT ?, c.product_id, c.quantity, c.position - 1
+		FROM UNNEST(?::uuid[], ?::int[]) WITH ORDINALITY AS c(product_id, quantity, position)
+	`), bundleId, pq.Array(ids), pq.Array(quantities))
+	if err != nil {
+		log.Error().Err(err).Str("bundle_id", bundleId).Msg("repository::setBundleComponents - Failed to insert components")
+		return err
+	}
+
+	return nil
+}
+
+// getBundleComponents returns the components of a bundle in the order they
+// were given. Deleted and unpublished components are only listed to their
+// owner, userId.
+func getBundleComponents(ctx context.Context, q sqlx.ExtContext, bundleId, userId string) ([]entity.BundleComponent, error) {
+	var components = make([]entity.BundleComponent, 0)
+
+	err := sqlx.SelectContext(ctx, q, &components, q.Rebind(`
+		SELECT
+			cp.id as product_id,
+			cp.slug,
+			cp.name,
+			cp.price,
+			COALESCE(cp.image_url, '') as image_url,
+			bi.quantity,
+			`+componentStock+` as stock
+		FROM product_bundle_items bi
+		JOIN products cp ON cp.id = bi.product_id
+		WHERE bi.bundle_id = ?
+			AND (`+componentVisible+` OR cp.user_id::text = ?)
+		ORDER BY bi.position, cp.id
+	`), bundleId, userId)
+	if err != nil {
+		log.Error().Err(err).Str("bundle_id", bundleId).Msg("repository::getBundleComponents - Failed to get components")
+		return nil, err
+	}
+
+	return components, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// maxCategoryDepth guards the recursive queries against cycles left behind by
+// manual data fixes.
+const maxCategoryDepth = 100
+
+func (r *shopRepository) GetCategoryTree(ctx context.Context, req *entity.GetCategoryTreeRequest) (*entity.GetCategoryTreeResponse, error) {
+	var (
+		resp  = new(entity.GetCategoryTreeResponse)
+		items = make([]entity.CategoryItem, 0)
+	)
+
+	query := `
+		SELECT id, name, user_id, parent_id
+		FROM categories
+		WHERE user_id = ?
+		AND deleted_at IS NULL
+		ORDER BY name
+	`
+
+	err := r.db.SelectContext(ctx, &items, r.db.Rebind(query), req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetCategoryTree - Failed to get categories")
+		return nil, err
+	}
+
+	resp.Categories = buildCategoryTree(items)
+
+	return resp, nil
+}
+
+func (r *shopRepository) MoveCategory(ctx context.Context, req *entity.MoveCategoryRequest) (*entity.MoveCategoryResponse, error) {
+	var resp = new(entity.MoveCategoryResponse)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::MoveCategory - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if req.ParentId != "" {
+		if err := r.checkCategoryParent(ctx, tx, req.UserId, req.Id, req.ParentId); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		UPDATE categories
+		SET parent_id = NULLIF(?, '')::uuid, updated_at = NOW()
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		RETURNING id, parent_id
+	`
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.ParentId, req.Id, req.UserId).StructScan(resp)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Err(err).Any("payload", req).Msg("repository::MoveCategory - Category not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Kategori tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::MoveCategory - Failed to move category")
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::MoveCategory - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) UpdateCategory(ctx context.Context, req *entity.UpdateCategoryRequest) (*entity.UpdateCategoryResponse, error) {
+	var resp = new(entity.UpdateCategoryResponse)
+
+	query := `
+		UPDATE categories
+		SET name = ?, updated_at = NOW()
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		RETURNING id, name, user_id, parent_id
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Name, req.Id, req.UserId).StructScan(resp)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Err(err).Any("payload", req).Msg("repository::UpdateCategory - Category not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Kategori tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateCategory - Failed to update category")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// checkCategoryParent makes sure parentId is an active category of the user and,
+// when id is set, that it is neither the category itself nor one of its descendants.
+func (r *shopRepository) checkCategoryParent(ctx context.Context, q sqlx.QueryerContext, userId, id, parentId string) error {
+	var exists bool
+
+	err := sqlx.GetContext(ctx, q, &exists, r.db.Rebind(`
+		SELECT EXISTS (
+			SELECT 1 FROM categories
+			WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		)
+	`), parentId, userId)
+	if err != nil {
+		log.Error().Err(err).Str("parent_id", parentId).Msg("repository::checkCategoryParent - Failed to check parent")
+		return err
+	}
+	if !exists {
+		return errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Kategori induk tidak ditemukan"),
+			errmsg.WithErrors("parentId", "kategori induk tidak ditemukan."),
+		)
+	}
+
+	if id == "" {
+		return nil
+	}
+
+	var cyclic bool
+
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id, 0 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT ch.id, t.depth + 1 FROM categories ch
+			JOIN tree t ON ch.parent_id = t.id
+			WHERE t.depth < ?
+		)
+		SELECT EXISTS (SELECT 1 FROM tree WHERE id = ?)
+	`
+
+	err = sqlx.GetContext(ctx, q, &cyclic, r.db.Rebind(query), id, maxCategoryDepth, parentId)
+	if err != nil {
+		log.Error().Err(err).Str("parent_id", parentId).Msg("repository::checkCategoryParent - Failed to check descendants")
+		return err
+	}
+	if cyclic {
+		return errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Kategori tidak dapat dipindahkan ke dalam dirinya sendiri"),
+			errmsg.WithErrors("parentId", "kategori induk tidak boleh kategori itu sendiri atau turunannya."),
+		)
+	}
+
+	return nil
+}
+
+// getCategoryBreadcrumbs returns the path from the root category down to categoryId.
+func (r *shopRepository) getCategoryBreadcrumbs(ctx context.Context, categoryId string) ([]entity.CategoryBreadcrumb, error) {
+	var breadcrumbs = make([]entity.CategoryBreadcrumb, 0)
+
+	query := `
+		WITH RECURSIVE path AS (
+			SELECT id, name, parent_id, 0 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id, c.name, c.parent_id, path.depth + 1 FROM categories c
+			JOIN path ON c.id = path.parent_id
+			WHERE path.depth < ?
+		)
+		SELECT id, name FROM path ORDER BY depth DESC
+	`
+
+	err := r.db.SelectContext(ctx, &breadcrumbs, r.db.Rebind(query), categoryId, maxCategoryDepth)
+	if err != nil {
+		log.Error().Err(err).Str("category_id", categoryId).Msg("repository::getCategoryBreadcrumbs - Failed to get breadcrumbs")
+		return nil, err
+	}
+
+	return breadcrumbs, nil
+}
+
+// buildCategoryTree nests the flat category list under their parents. Categories
+// whose parent is missing from the list are treated as roots.
+func buildCategoryTree(items []entity.CategoryItem) []entity.CategoryNode {
+	var (
+		children = make(map[string][]entity.CategoryItem)
+		known    = make(map[string]bool, len(items))
+		roots    = make([]entity.CategoryItem, 0)
+	)
+
+	for _, item := range items {
+		known[item.Id] = true
+	}
+
+	for _, item := range items {
+		if item.ParentId == nil || !known[*item.ParentId] {
+			roots = append(roots, item)
+			continue
+		}
+		children[*item.ParentId] = append(children[*item.ParentId], item)
+	}
+
+	var build func(items []entity.CategoryItem, depth int) []entity.CategoryNode
+	build = func(items []entity.CategoryItem, depth int) []entity.CategoryNode {
+		nodes := make([]entity.CategoryNode, 0, len(items))
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+		for _, item := range items {
+			node := entity.CategoryNode{
+				Id:       item.Id,
+				Name:     item.Name,
+				ParentId: item.ParentId,
+				Children: make([]entity.CategoryNode, 0),
+			}
+			if depth < maxCategoryDepth {
+				node.Children = build(children[item.Id], depth+1)
+			}
+			nodes = append(nodes, node)
+		}
+
+		return nodes
+	}
+
+	return build(roots, 0)
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/types"
+	"context"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// shopCurrency is the currency the prices of product p are in.
+const shopCurrency = `(SELECT s.currency FROM shops s WHERE s.id = p.shop_id)`
+
+// shopRate is the value in the default currency of one unit of the currency of
+// product p, NULL while no rate is known for it.
+const shopRate = `(
+	SELECT CASE WHEN s.currency = '` + types.DefaultCurrency + `' THEN 1 ELSE er.rate END
+	FROM shops s
+	LEFT JOIN exchange_rates er ON er.currency = s.currency
+	WHERE s.id = p.shop_id
+)`
+
+// SetExchangeRates upserts the given rates, the other currencies are kept.
+func (r *shopRepository) SetExchangeRates(ctx context.Context, req *entity.SetExchangeRatesRequest) error {
+	var (
+		currencies = make([]string, len(req.Rates))
+		rates      = make([]float64, len(req.Rates))
+	)
+
+	for i, rate := range req.Rates {
+		currencies[i] = rate.Currency
+		rates[i] = rate.Rate
+	}
+
+	_, err := r.db.ExecContext(ctx, r.db.Rebind(`
+		INSERT INTO exchange_rates (currency, rate)
+		SELECT t.currency, t.rate
+		FROM UNNEST(?::text[], ?::numeric[]) AS t(currency, rate)
+		ON CONFLICT (currency) DO UPDATE
+		SET rate = EXCLUDED.rate, updated_at = NOW()
+	`), pq.Array(currencies), pq.Array(rates))
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetExchangeRates - Failed to upsert rates")
+		return err
+	}
+
+	return nil
+}
+
+// GetExchangeRates returns every known rate by currency.
+func (r *shopRepository) GetExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	var rates = make([]entity.ExchangeRate, 0)
+
+	err := r.db.SelectContext(ctx, &rates, `
+		SELECT currency, rate, updated_at
+		FROM exchange_rates
+		ORDER BY currency
+	`)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::GetExchangeRates - Failed to get rates")
+		return nil, err
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"codebase-app/pkg"
+	"codebase-app/pkg/errmsg"
+	"encoding/json"
+)
+
+// shopCursorSort names the fixed newest-first order of the shop listing.
+const shopCursorSort = "shops_newest"
+
+// listCursor is the payload of the opaque cursors returned by the listings. It
+// remembers the sort it was made for and the sort values of the last row.
+type listCursor struct {
+	Sort string          `json:"s"`
+	Key  json.RawMessage `json:"k"`
+}
+
+func encodeListCursor(sort, key string) (string, error) {
+	return pkg.EncodeCursor(listCursor{Sort: sort, Key: json.RawMessage(key)})
+}
+
+// decodeListCursor returns the sort values stored in the cursor as strings so
+// they can be cast back to their sql types. size is the expected number of values.
+func decodeListCursor(cursor, sort string, size int) ([]any, error) {
+	var (
+		c   listCursor
+		raw []json.RawMessage
+	)
+
+	invalid := errmsg.NewCustomErrors(400,
+		errmsg.WithMessage("Cursor tidak valid"),
+		errmsg.WithErrors("cursor", "cursor tidak valid."),
+	)
+
+	if err := pkg.DecodeCursor(cursor, &c); err != nil || c.Sort != sort {
+		return nil, invalid
+	}
+
+	if err := json.Unmarshal(c.Key, &raw); err != nil || len(raw) != size {
+		return nil, invalid
+	}
+
+	values := make([]any, len(raw))
+	for i, r := range raw {
+		if len(r) > 0 && r[0] == '"' {
+			var s string
+			if err := json.Unmarshal(r, &s); err != nil {
+				return nil, invalid
+			}
+			values[i] = s
+			continue
+		}
+		values[i] = string(r)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+func (r *shopRepository) CheckShopOwner(ctx context.Context, shopId, userId string) error {
+	var exists bool
+
+	err := r.db.GetContext(ctx, &exists, r.db.Rebind(`
+		SELECT EXISTS (SELECT 1 FROM shops WHERE id = ? AND user_id = ? AND deleted_at IS NULL)
+	`), shopId, userId)
+	if err != nil {
+		log.Error().Err(err).Str("shop_id", shopId).Msg("repository::CheckShopOwner - Failed to check shop")
+		return err
+	}
+	if !exists {
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+	}
+
+	return nil
+}
+
+// ExportProducts runs the listing query without paging and hands the rows to
+// fn one at a time, so the catalog is never held in memory.
+func (r *shopRepository) ExportProducts(ctx context.Context, req *entity.ExportProductsRequest, fn func(*entity.ExportProductItem) error) error {
+	type dao struct {
+		entity.ExportProductItem
+		ImageURLs pq.StringArray `db:"image_urls"`
+	}
+
+	query := `
+		SELECT
+			p.id,
+			p.name,
+			p.description,
+			p.merk,
+			p.category_id,
+			c.name as category_name,
+			p.price,
+			COALESCE(va.min_price, p.price) as min_price,
+			COALESCE(va.max_price, p.price) as max_price,
+			p.stock,
+			COALESCE(va.total_stock, p.stock) as total_stock,
+			p.rating,
+			p.review_count,
+			p.status,
+			p.publish_at,
+			COALESCE(p.image_url, '') as image_url,
+			ARRAY(
+				SELECT i.url FROM product_images i
+				WHERE i.product_id = p.id
+				ORDER BY i.is_primary DESC, i.position, i.created_at
+			) as image_urls
+		FROM products p
+		JOIN categories c ON p.category_id = c.id
+		LEFT JOIN LATERAL (
+			SELECT
+				MIN(COALESCE(v.price, p.price)) as min_price,
+				MAX(COALESCE(v.price, p.price)) as max_price,
+				SUM(v.stock) as total_stock
+			FROM product_variants v
+			WHERE v.product_id = p.id
+				AND v.deleted_at IS NULL
+		) va ON true
+		WHERE p.deleted_at IS NULL
+	`
+
+	filter, args := productFilter(&req.Filter)
+	query += filter
+
+	order, orderArgs := productOrder(&req.Filter).order()
+	query += order
+	args = append(args, orderArgs...)
+
+	rows, err := r.db.QueryxContext(ctx, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ExportProducts - Failed to query products")
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d dao
+		if err := rows.StructScan(&d); err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::ExportProducts - Failed to scan product")
+			return err
+		}
+
+		d.ExportProductItem.ImageURLs = []string(d.ImageURLs)
+		if err := fn(&d.ExportProductItem); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ExportProducts - Failed to read products")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/types"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// facetLimit caps the number of buckets returned for the free-text facets.
+const facetLimit = 20
+
+// getProductFacets counts the products per merk, category, rating and price
+// range. Every facet is computed with all active filters except its own, so the
+// counts tell how many results picking another value of that facet would give.
+func (r *shopRepository) getProductFacets(ctx context.Context, req *entity.GetProductRequest) (*entity.ProductFacets, error) {
+	var (
+		facets = new(entity.ProductFacets)
+		err    error
+	)
+
+	merkReq := *req
+	merkReq.Merk = ""
+	merkReq.BrandId = ""
+	facets.Merk, err = r.countProductFacet(ctx, &merkReq, "p.merk")
+	if err != nil {
+		return nil, err
+	}
+
+	categoryReq := *req
+	categoryReq.Category = ""
+	categoryReq.CategoryId = ""
+	facets.Category, err = r.countProductFacet(ctx, &categoryReq, "c.name")
+	if err != nil {
+		return nil, err
+	}
+
+	ratingReq := *req
+	ratingReq.Rating = 0
+	ratingReq.MinRating = 0
+	facets.Rating, err = r.countProductFacet(ctx, &ratingReq, "FLOOR(p.rating)::int::text")
+	if err != nil {
+		return nil, err
+	}
+
+	priceReq := *req
+	priceReq.MinPrice = types.Money{}
+	priceReq.MaxPrice = types.Money{}
+	facets.PriceRange, err = r.countPriceRanges(ctx, &priceReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+func (r *shopRepository) countProductFacet(ctx context.Context, req *entity.GetProductRequest, column string) ([]entity.FacetBucket, error) {
+	var buckets = make([]entity.FacetBucket, 0)
+
+	query := fmt.Sprintf(`
+		SELECT
+			%s as value,
+			COUNT(p.id) as count
+		FROM products p
+		JOIN categories c ON p.category_id = c.id
+		WHERE p.deleted_at IS NULL
+	`, column)
+
+	filter, args := productFilter(req)
+	query += filter
+	query += fmt.Sprintf(`
+		GROUP BY 1
+		ORDER BY count DESC, value
+		LIMIT %d
+	`, facetLimit)
+
+	err := r.db.SelectContext(ctx, &buckets, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Str("column", column).Msg("repository::countProductFacet - Failed to count facet")
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+func (r *shopRepository) countPriceRanges(ctx context.Context, req *entity.GetProductRequest) ([]entity.PriceRangeBucket, error) {
+	var (
+		bounds  = entity.PriceRangeBounds
+		columns = make([]string, len(bounds))
+		args    = make([]any, 0, len(bounds)*2)
+		counts  = make([]any, len(bounds))
+		values  = make([]int, len(bounds))
+		buckets = make([]entity.PriceRangeBucket, len(bounds))
+	)
+
+	for i, lower := range bounds {
+		if i == len(bounds)-1 {
+			columns[i] = "COUNT(p.id) FILTER (WHERE " + productBasePrice + " >= ?)"
+			args = append(args, lower)
+		} else {
+			columns[i] = "COUNT(p.id) FILTER (WHERE " + productBasePrice + " >= ? AND " + productBasePrice + " < ?)"
+			args = append(args, lower, bounds[i+1])
+		}
+		counts[i] = &values[i]
+	}
+
+	query := `
+		SELECT ` + strings.Join(columns, ", ") + `
+		FROM products p
+		JOIN categories c ON p.category_id = c.id
+		WHERE p.deleted_at IS NULL
+	`
+
+	filter, filterArgs := productFilter(req)
+	query += filter
+	args = append(args, filterArgs...)
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), args...).Scan(counts...)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::countPriceRanges - Failed to count price ranges")
+		return nil, err
+	}
+
+	for i, lower := range bounds {
+		buckets[i] = entity.PriceRangeBucket{Min: lower, Count: values[i]}
+		if i < len(bounds)-1 {
+			upper := bounds[i+1]
+			buckets[i].Max = &upper
+		}
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,357 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+func (r *shopRepository) GetProductImages(ctx context.Context, req *entity.GetProductImagesRequest) (*entity.GetProductImagesResponse, error) {
+	var (
+		resp   = new(entity.GetProductImagesResponse)
+		exists bool
+	)
+
+	err := r.db.GetContext(ctx, &exists, r.db.Rebind(`
+		SELECT EXISTS (
+			SELECT 1 FROM products p
+			WHERE p.id = ?
+				AND p.deleted_at IS NULL
+				AND (`+productVisible+` OR p.user_id::text = ?)
+		)
+	`), req.ProductId, req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetProductImages - Failed to check product")
+		return nil, err
+	}
+	if !exists {
+		return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+	}
+
+	images, err := r.getImagesByProductIds(ctx, []string{req.ProductId})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Images = images[req.ProductId]
+	if resp.Images == nil {
+		resp.Images = make([]entity.ProductImage, 0)
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) AddProductImages(ctx context.Context, req *entity.AddProductImagesRequest) (*entity.GetProductImagesResponse, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::AddProductImages - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := r.checkProductOwner(ctx, tx, req.ProductId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	before, err := getProductSnapshot(ctx, tx, req.ProductId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertProductImages(ctx, tx, req.ProductId, req.ImageURLs, false); err != nil {
+		return nil, err
+	}
+
+	// only a change of the primary image shows up in the revision
+	if _, err := recordProductRevision(ctx, tx, req.ProductId, req.UserId, entity.RevisionActionUpdate, before, ""); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::AddProductImages - Failed to commit transaction")
+		return nil, err
+	}
+
+	return r.GetProductImages(ctx, &entity.GetProductImagesRequest{UserId: req.UserId, ProductId: req.ProductId})
+}
+
+func (r *shopRepository) ReorderProductImages(ctx context.Context, req *entity.ReorderProductImagesRequest) (*entity.GetProductImagesResponse, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::ReorderProductImages - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := r.checkProductOwner(ctx, tx, req.ProductId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	var total int
+	err = tx.GetContext(ctx, &total, tx.Rebind(`
+		SELECT COUNT(*) FROM product_images WHERE product_id = ?
+	`), req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ReorderProductImages - Failed to count images")
+		return nil, err
+	}
+
+	// the new order must mention every image of the product exactly once
+	query := `
+		UPDATE product_images i
+		SET position = x.position - 1, updated_at = NOW()
+		FROM UNNEST(?::uuid[]) WITH ORDINALITY AS x(id, position)
+		WHERE i.id = x.id AND i.product_id = ?
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), pq.Array(req.ImageIds), req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ReorderProductImages - Failed to reorder images")
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ReorderProductImages - Failed to get affected rows")
+		return nil, err
+	}
+
+	if int(affected) != total || len(req.ImageIds) != total {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Urutan gambar tidak valid"),
+			errmsg.WithErrors("imageIds", "imageIds harus berisi semua gambar produk."),
+		)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::ReorderProductImages - Failed to commit transaction")
+		return nil, err
+	}
+
+	return r.GetProductImages(ctx, &entity.GetProductImagesRequest{UserId: req.UserId, ProductId: req.ProductId})
+}
+
+func (r *shopRepository) SetPrimaryProductImage(ctx context.Context, req *entity.SetPrimaryProductImageRequest) (*entity.GetProductImagesResponse, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::SetPrimaryProductImage - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := r.checkProductOwner(ctx, tx, req.ProductId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	before, err := getProductSnapshot(ctx, tx, req.ProductId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	err = tx.GetContext(ctx, &exists, tx.Rebind(`
+		SELECT EXISTS (SELECT 1 FROM product_images WHERE id = ? AND product_id = ?)
+	`), req.Id, req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetPrimaryProductImage - Failed to check image")
+		return nil, err
+	}
+	if !exists {
+		return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Gambar tidak ditemukan"))
+	}
+
+	if err := setPrimaryImage(ctx, tx, req.ProductId, req.Id); err != nil {
+		return nil, err
+	}
+
+	if _, err := recordProductRevision(ctx, tx, req.ProductId, req.UserId, entity.RevisionActionUpdate, before, ""); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetPrimaryProductImage - Failed to commit transaction")
+		return nil, err
+	}
+
+	return r.GetProductImages(ctx, &entity.GetProductImagesRequest{UserId: req.UserId, ProductId: req.ProductId})
+}
+
+func (r *shopRepository) DeleteProductImage(ctx context.Context, req *entity.DeleteProductImageRequest) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::DeleteProductImage - Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.checkProductOwner(ctx, tx, req.ProductId, req.UserId); err != nil {
+		return err
+	}
+
+	before, err := getProductSnapshot(ctx, tx, req.ProductId, req.UserId)
+	if err != nil {
+		return err
+	}
+
+	var wasPrimary bool
+	err = tx.GetContext(ctx, &wasPrimary, tx.Rebind(`
+		DELETE FROM product_images
+		WHERE id = ? AND product_id = ?
+		RETURNING is_primary
+	`), req.Id, req.ProductId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errmsg.NewCustomErrors(404, errmsg.WithMessage("Gambar tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteProductImage - Failed to delete image")
+		return err
+	}
+
+	if wasPrimary {
+		// promote the next image in the gallery, if any
+		if err := setPrimaryImage(ctx, tx, req.ProductId, ""); err != nil {
+			return err
+		}
+	}
+
+	if _, err := recordProductRevision(ctx, tx, req.ProductId, req.UserId, entity.RevisionActionUpdate, before, ""); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteProductImage - Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// insertProductImages appends the urls to the end of the product gallery. When
+// primary is true or the product has no primary image yet, the first url becomes
+// the primary image.
+func insertProductImages(ctx context.Context, tx *sqlx.Tx, productId string, urls []string, primary bool) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	var (
+		ids        = make([]string, 0, len(urls))
+		hasPrimary bool
+	)
+
+	err := tx.GetContext(ctx, &hasPrimary, tx.Rebind(`
+		SELECT EXISTS (SELECT 1 FROM product_images WHERE product_id = ? AND is_primary)
+	`), productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::insertProductImages - Failed to check primary image")
+		return err
+	}
+
+	query := `
+		INSERT INTO product_images (product_id, url, position)
+		VALUES (?, ?, (SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = ?))
+		RETURNING id
+	`
+
+	for _, url := range urls {
+		var id string
+		err := tx.QueryRowxContext(ctx, tx.Rebind(query), productId, url, productId).Scan(&id)
+		if err != nil {
+			log.Error().Err(err).Str("product_id", productId).Msg("repository::insertProductImages - Failed to insert image")
+			return err
+		}
+		ids = append(ids, id)
+	}
+
+	if primary || !hasPrimary {
+		return setPrimaryImage(ctx, tx, productId, ids[0])
+	}
+
+	return nil
+}
+
+// setPrimaryImage marks imageId as the primary image of the product and keeps
+// products.image_url in sync with it. An empty imageId promotes the first image
+// of the gallery.
+func setPrimaryImage(ctx context.Context, tx *sqlx.Tx, productId, imageId string) error {
+	_, err := tx.ExecContext(ctx, tx.Rebind(`
+		UPDATE product_images
+		SET is_primary = false, updated_at = NOW()
+		WHERE product_id = ? AND is_primary
+	`), productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::setPrimaryImage - Failed to reset primary image")
+		return err
+	}
+
+	query := `
+		UPDATE product_images
+		SET is_primary = true, updated_at = NOW()
+		WHERE id = COALESCE(NULLIF(?, '')::uuid, (
+			SELECT id FROM product_images
+			WHERE product_id = ?
+			ORDER BY position, created_at
+			LIMIT 1
+		))
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), imageId, productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::setPrimaryImage - Failed to set primary image")
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		UPDATE products
+		SET image_url = (SELECT url FROM product_images WHERE product_id = ? AND is_primary),
+			updated_at = NOW()
+		WHERE id = ?
+	`), productId, productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::setPrimaryImage - Failed to sync product image")
+		return err
+	}
+
+	return nil
+}
+
+// getImagesByProductIds returns the galleries grouped by product id with the
+// primary image first.
+func (r *shopRepository) getImagesByProductIds(ctx context.Context, productIds []string) (map[string][]entity.ProductImage, error) {
+	var (
+		result = make(map[string][]entity.ProductImage)
+		images = make([]entity.ProductImage, 0)
+	)
+
+	if len(productIds) == 0 {
+		return result, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT id, product_id, url, position, is_primary
+		FROM product_images
+		WHERE product_id IN (?)
+		ORDER BY is_primary DESC, position, created_at
+	`, productIds)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::getImagesByProductIds - Failed to construct image query")
+		return nil, err
+	}
+
+	err = r.db.SelectContext(ctx, &images, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::getImagesByProductIds - Failed to get images")
+		return nil, err
+	}
+
+	for _, image := range images {
+		result[image.ProductId] = append(result[image.ProductId], image)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// importBatchSize is the number of rows inserted per transaction.
+const importBatchSize = 100
+
+// ImportProducts inserts the rows in batches. A failing row is rolled back to
+// its savepoint and reported, the rest of the batch is still committed.
+func (r *shopRepository) ImportProducts(ctx context.Context, req *entity.ImportProductsRequest, rows []entity.ImportProductRow) ([]entity.ImportRowResult, error) {
+	results := make([]entity.ImportRowResult, 0, len(rows))
+
+	if err := r.CheckShopOwner(ctx, req.ShopId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(rows); start += importBatchSize {
+		end := min(start+importBatchSize, len(rows))
+
+		batch, err := r.importProductBatch(ctx, rows[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, batch...)
+	}
+
+	return results, nil
+}
+
+func (r *shopRepository) importProductBatch(ctx context.Context, rows []entity.ImportProductRow) ([]entity.ImportRowResult, error) {
+	results := make([]entity.ImportRowResult, 0, len(rows))
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::importProductBatch - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, row := range rows {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT import_row"); err != nil {
+			log.Error().Err(err).Msg("repository::importProductBatch - Failed to create savepoint")
+			return nil, err
+		}
+
+		product, err := insertProduct(ctx, tx, &row.Product)
+		if err != nil {
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT import_row"); err != nil {
+				log.Error().Err(err).Msg("repository::importProductBatch - Failed to rollback savepoint")
+				return nil, err
+			}
+
+			results = append(results, entity.ImportRowResult{
+				Row:    row.Row,
+				Status: entity.ImportStatusFailed,
+				Errors: errmsg.FieldErrors[error](err),
+			})
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT import_row"); err != nil {
+			log.Error().Err(err).Msg("repository::importProductBatch - Failed to release savepoint")
+			return nil, err
+		}
+
+		results = append(results, entity.ImportRowResult{
+			Row:       row.Row,
+			Status:    entity.ImportStatusImported,
+			ProductId: product.Id,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("repository::importProductBatch - Failed to commit transaction")
+		return nil, err
+	}
+
+	return results, nil
+}
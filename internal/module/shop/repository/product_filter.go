@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// productVisible is the condition for a product to show up in public views,
+// scheduled products appear once their publish_at has passed.
+const productVisible = "p.status = 'published' AND (p.publish_at IS NULL OR p.publish_at <= NOW())"
+
+// productOnSale is the condition for the sale price of p to apply right now.
+const productOnSale = "p.sale_price IS NOT NULL AND (p.sale_starts_at IS NULL OR p.sale_starts_at <= NOW()) AND (p.sale_ends_at IS NULL OR p.sale_ends_at > NOW())"
+
+// productPrice is the price paid for p right now, in the currency of its shop.
+const productPrice = "(CASE WHEN " + productOnSale + " THEN p.sale_price ELSE p.price END)"
+
+// productBasePrice is productPrice in the default currency, so shops selling
+// in other currencies compare fairly. Price filters, sorting and the price
+// facet use it, it is NULL while the rate of the shop currency is unknown.
+const productBasePrice = "ROUND(" + productPrice + " * " + shopRate + ", 2)"
+
+// productFilter builds the conditions shared by every product listing query.
+// The result is meant to be appended after "WHERE p.deleted_at IS NULL" in a
+// query that joins products as p and categories as c.
+func productFilter(req *entity.GetProductRequest) (string, []any) {
+	var (
+		query strings.Builder
+		args  []any
+	)
+
+	if !req.AllStatuses {
+		query.WriteString(" AND " + productVisible)
+	}
+	if tsquery := pkg.FormatKeywords(req.Keyword); tsquery != "" {
+		query.WriteString(" AND p.search_vector @@ to_tsquery('simple', ?)")
+		args = append(args, tsquery)
+	}
+	if req.Category != "" {
+		query.WriteString(" AND c.name ILIKE ?")
+		args = append(args, "%"+req.Category+"%")
+	}
+	if req.CategoryId != "" {
+		query.WriteString(` AND p.category_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE id = ? AND deleted_at IS NULL
+				UNION
+				SELECT ch.id FROM categories ch
+				JOIN tree t ON ch.parent_id = t.id
+				WHERE ch.deleted_at IS NULL
+			)
+			SELECT id FROM tree
+		)`)
+		args = append(args, req.CategoryId)
+	}
+	if req.Rating != 0 {
+		query.WriteString(" AND FLOOR(p.rating) = ?")
+		args = append(args, req.Rating)
+	}
+	if req.Merk != "" {
+		query.WriteString(" AND p.merk ILIKE ?")
+		args = append(args, "%"+req.Merk+"%")
+	}
+	if req.BrandId != "" {
+		query.WriteString(" AND p.brand_id = ?")
+		args = append(args, req.BrandId)
+	}
+	if req.MinPrice.Amount > 0 {
+		query.WriteString(" AND " + productBasePrice + " >= ?")
+		args = append(args, req.MinPrice)
+	}
+	if req.MaxPrice.Amount > 0 {
+		query.WriteString(" AND " + productBasePrice + " <= ?")
+		args = append(args, req.MaxPrice)
+	}
+	if req.InStock {
+		// products with variants are in stock when any active variant is,
+		// bundles when every component has enough for one bundle
+		query.WriteString(` AND COALESCE(` + bundleStock + `, (
+			SELECT SUM(v.stock) FROM product_variants v
+			WHERE v.product_id = p.id AND v.deleted_at IS NULL
+		), p.stock) > 0`)
+	}
+	if req.ShopId != "" {
+		query.WriteString(" AND p.shop_id = ?")
+		args = append(args, req.ShopId)
+	}
+	if req.MinRating != 0 {
+		query.WriteString(" AND p.rating >= ?")
+		args = append(args, req.MinRating)
+	}
+
+	// codes are sorted so the same filter always builds the same query
+	codes := make([]string, 0, len(req.Attributes))
+	for code := range req.Attributes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	// a value is compared in the stored form of the attribute type, only the
+	// attributes the product has through its current category count
+	for _, code := range codes {
+		var (
+			n        = len(req.Attributes[code])
+			texts    = make([]string, 0, n)
+			numbers  = make([]string, 0, n)
+			units    = make([]string, 0, n)
+			booleans = make([]string, 0, n)
+		)
+		for _, raw := range req.Attributes[code] {
+			v := entity.NewAttributeFilterValue(raw)
+			texts = append(texts, v.Text)
+			numbers = append(numbers, v.Number)
+			units = append(units, v.Unit)
+			booleans = append(booleans, v.Boolean)
+		}
+
+		query.WriteString(` AND EXISTS (` + effectiveAttributes("p.category_id") + `
+			SELECT 1 FROM attrs
+			JOIN product_attributes pa ON pa.attribute_id = attrs.id
+			JOIN UNNEST(?::text[], ?::text[], ?::text[], ?::text[]) AS f(text, number, unit, boolean) ON CASE attrs.type
+				WHEN 'number' THEN pa.value = f.number AND (f.unit = '' OR LOWER(f.unit) = LOWER(attrs.unit))
+				WHEN 'boolean' THEN pa.value = f.boolean
+				ELSE LOWER(pa.value) = f.text
+			END
+			WHERE pa.product_id = p.id AND attrs.code = ?
+		)`)
+		args = append(args, maxCategoryDepth, pq.Array(texts), pq.Array(numbers), pq.Array(units), pq.Array(booleans), code)
+	}
+
+	if tags := entity.NormalizeTags(req.Tags); len(tags) > 0 {
+		query.WriteString(` AND (
+			SELECT COUNT(*) FROM product_tags pt
+			JOIN tags t ON t.id = pt.tag_id
+			WHERE pt.product_id = p.id AND t.name = ANY(?::text[])
+		) = ?`)
+		args = append(args, pq.Array(tags), len(tags))
+	}
+
+	return query.String(), args
+}
+
+// productSort describes the ordering of a product listing. Every column is
+// sorted in the same direction and p.id is always the last column, which keeps
+// the order total so it can be used for keyset pagination.
+type productSort struct {
+	name    string
+	columns []string // sql expressions, p.id excluded
+	casts   []string // sql types to cast cursor values back to, one per column
+	desc    bool
+	args    []any // args needed by each occurrence of the columns
+}
+
+// productOrder picks the ordering of the product listing. Keyword searches are
+// ranked by relevance unless another sort is requested.
+func productOrder(req *entity.GetProductRequest) productSort {
+	switch req.Sort {
+	// products without a known rate come last, the keyset comparison does
+	// not work on NULL
+	case entity.SortPriceAsc:
+		return productSort{
+			name:    entity.SortPriceAsc,
+			columns: []string{"(" + shopRate + " IS NULL)", "COALESCE(" + productBasePrice + ", 0)"},
+			casts:   []string{"boolean", "numeric"},
+		}
+	case entity.SortPriceDesc:
+		return productSort{
+			name:    entity.SortPriceDesc,
+			columns: []string{"(" + shopRate + " IS NOT NULL)", "COALESCE(" + productBasePrice + ", 0)"},
+			casts:   []string{"boolean", "numeric"},
+			desc:    true,
+		}
+	case entity.SortRating:
+		return productSort{name: entity.SortRating, columns: []string{"p.rating", "p.created_at"}, casts: []string{"numeric", "timestamptz"}, desc: true}
+	case entity.SortName:
+		return productSort{name: entity.SortName, columns: []string{"p.name"}, casts: []string{"text"}}
+	case entity.SortNewest:
+		return productSort{name: entity.SortNewest, columns: []string{"p.created_at"}, casts: []string{"timestamptz"}, desc: true}
+	}
+
+	if tsquery := pkg.FormatKeywords(req.Keyword); tsquery != "" {
+		return productSort{
+			name:    entity.SortRelevance,
+			columns: []string{"ts_rank(p.search_vector, to_tsquery('simple', ?))"},
+			casts:   []string{"real"},
+			desc:    true,
+			args:    []any{tsquery},
+		}
+	}
+
+	return productSort{name: entity.SortNewest, columns: []string{"p.created_at"}, casts: []string{"timestamptz"}, desc: true}
+}
+
+// keyColumns returns the sort columns followed by the p.id tie breaker.
+func (s productSort) keyColumns() []string {
+	return append(append(make([]string, 0, len(s.columns)+1), s.columns...), "p.id")
+}
+
+// key selects the sort values of a row as a json array, used to build cursors.
+func (s productSort) key() (string, []any) {
+	return "json_build_array(" + strings.Join(s.keyColumns(), ", ") + ")::text", s.args
+}
+
+// after builds the keyset condition for rows that come after the cursor values.
+func (s productSort) after(values []any) (string, []any) {
+	var (
+		placeholders = make([]string, 0, len(s.casts)+1)
+		args         = append([]any{}, s.args...)
+		op           = ">"
+	)
+
+	if s.desc {
+		op = "<"
+	}
+
+	for _, cast := range s.casts {
+		placeholders = append(placeholders, "?::"+cast)
+	}
+	placeholders = append(placeholders, "?::uuid")
+
+	args = append(args, values...)
+
+	return " AND (" + strings.Join(s.keyColumns(), ", ") + ") " + op + " (" + strings.Join(placeholders, ", ") + ")", args
+}
+
+// order builds the ORDER BY clause.
+func (s productSort) order() (string, []any) {
+	var (
+		dir     = " ASC"
+		columns = make([]string, 0, len(s.columns)+1)
+	)
+
+	if s.desc {
+		dir = " DESC"
+	}
+
+	for _, column := range s.keyColumns() {
+		columns = append(columns, column+dir)
+	}
+
+	return " ORDER BY " + strings.Join(columns, ", "), s.args
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// relatedPoolSize caps the candidates fetched for ranking, the closest ones by
+// category, brand and price come first.
+const relatedPoolSize = 200
+
+const relatedColumns = `
+	p.id,
+	p.slug,
+	p.name,
+	p.shop_id,
+	p.category_id,
+	c.parent_id as parent_category_id,
+	p.brand_id,
+	p.merk,
+	` + productPrice + ` as price,
+	` + productBasePrice + ` as base_price,
+	p.rating,
+	p.review_count,
+	COALESCE(p.image_url, '') as image_url
+`
+
+// GetRelatedTarget returns the product the related products are looked up
+// for, the owner may look up a product that is not public yet.
+func (r *shopRepository) GetRelatedTarget(ctx context.Context, req *entity.GetRelatedProductsRequest) (*entity.RelatedCandidate, error) {
+	var target = new(entity.RelatedCandidate)
+
+	err := r.db.GetContext(ctx, target, r.db.Rebind(`
+		SELECT `+relatedColumns+`
+		FROM products p
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE p.id = ?
+			AND p.deleted_at IS NULL
+			AND (`+productVisible+` OR p.user_id::text = ?)
+	`), req.Id, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetRelatedTarget - Failed to get product")
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// GetRelatedCandidates returns the public, in stock products sharing the
+// category tree branch, the brand or a name word with the target.
+func (r *shopRepository) GetRelatedCandidates(ctx context.Context, target *entity.RelatedCandidate) ([]entity.RelatedCandidate, error) {
+	var (
+		candidates = make([]entity.RelatedCandidate, 0)
+		brandId    string
+		parentId   string
+	)
+
+	if target.BrandId != nil {
+		brandId = *target.BrandId
+	}
+	if target.ParentCategoryId != nil {
+		parentId = *target.ParentCategoryId
+	}
+
+	filter, filterArgs := productFilter(&entity.GetProductRequest{InStock: true})
+
+	// same category, its parent, its children or its siblings
+	pool := []string{
+		"p.category_id = ?",
+		"c.parent_id = ?",
+		"p.category_id::text = ?",
+		"c.parent_id::text = ?",
+		"p.brand_id::text = ?",
+	}
+	args := []any{target.CategoryId, target.CategoryId, parentId, parentId, brandId}
+
+	if tokens := entity.NameTokens(target.Name); len(tokens) > 0 {
+		pool = append(pool, "p.search_vector @@ to_tsquery('simple', ?)")
+		args = append(args, strings.Join(tokens, ":* | ")+":*")
+	}
+
+	query := `
+		SELECT ` + relatedColumns + `
+		FROM products p
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE p.deleted_at IS NULL
+			AND p.id <> ?` + filter + `
+			AND (` + strings.Join(pool, " OR ") + `)
+		ORDER BY
+			(p.category_id = ?) DESC,
+			COALESCE(p.brand_id::text = ?, false) DESC,
+			ABS(` + productBasePrice + ` - ?::numeric),
+			p.id
+		LIMIT ?
+	`
+	args = append(append([]any{target.Id}, filterArgs...), args...)
+	args = append(args, target.CategoryId, brandId, target.BasePrice, relatedPoolSize)
+
+	err := r.db.SelectContext(ctx, &candidates, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", target.Id).Msg("repository::GetRelatedCandidates - Failed to get candidates")
+		return nil, err
+	}
+
+	return candidates, nil
+}
@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"cmp"
 	"codebase-app/internal/module/shop/entity"
 	"codebase-app/internal/module/shop/ports"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
 	"context"
+	"database/sql"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog/log"
@@ -23,19 +27,38 @@ func NewShopRepository(db *sqlx.DB) *shopRepository {
 
 func (r *shopRepository) CreateShop(ctx context.Context, req *entity.CreateShopRequest) (*entity.CreateShopResponse, error) {
 	var resp = new(entity.CreateShopResponse)
-	// Your code here
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::CreateShop - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	slug, err := uniqueShopSlug(ctx, tx, req.Name, "")
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
-		INSERT INTO shops (user_id, name, description, terms)
-		VALUES (?, ?, ?, ?) RETURNING id
+		INSERT INTO shops (user_id, name, description, terms, slug, currency)
+		VALUES (?, ?, ?, ?, ?, ?) RETURNING id, slug
 	`
 
-	err := r.db.QueryRowContext(ctx, r.db.Rebind(query),
+	err = tx.QueryRowContext(ctx, tx.Rebind(query),
 		req.UserId,
 		req.Name,
 		req.Description,
-		req.Terms).Scan(&resp.Id)
+		req.Terms,
+		slug,
+		cmp.Or(req.Currency, types.DefaultCurrency)).Scan(&resp.Id, &resp.Slug)
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository::CreateShop - Failed to create shop")
+		return nil, slugConflict(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateShop - Failed to commit transaction")
 		return nil, err
 	}
 
@@ -46,7 +69,7 @@ func (r *shopRepository) GetShop(ctx context.Context, req *entity.GetShopRequest
 	var resp = new(entity.GetShopResponse)
 
 	shopQuery := `
-		SELECT name, description, terms
+		SELECT slug, name, description, terms, currency
 		FROM shops
 		WHERE id = $1
 	`
@@ -58,10 +81,11 @@ func (r *shopRepository) GetShop(ctx context.Context, req *entity.GetShopRequest
 	}
 
 	productQuery := `
-		SELECT id, name, description, price, stock, image_url
-		FROM products
-		WHERE shop_id = $1
-		AND deleted_at IS NULL
+		SELECT p.id, p.slug, p.name, p.description, p.price, ` + productPrice + ` as effective_price, p.stock, p.image_url, ` + shopCurrency + ` as currency
+		FROM products p
+		WHERE p.shop_id = $1
+		AND p.deleted_at IS NULL
+		AND ` + productVisible + `
 	`
 
 	products := []entity.ProductItem{}
@@ -95,21 +119,39 @@ func (r *shopRepository) DeleteShop(ctx context.Context, req *entity.DeleteShopR
 func (r *shopRepository) UpdateShop(ctx context.Context, req *entity.UpdateShopRequest) (*entity.UpdateShopResponse, error) {
 	var resp = new(entity.UpdateShopResponse)
 
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::UpdateShop - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	slug, err := reslugShop(ctx, tx, req.Id, req.UserId, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE shops
-		SET name = ?, description = ?, terms = ?, updated_at = NOW()
+		SET name = ?, description = ?, terms = ?, slug = ?, updated_at = NOW()
 		WHERE id = ? AND user_id = ?
-		RETURNING id
+		RETURNING id, slug
 	`
 
-	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query),
 		req.Name,
 		req.Description,
 		req.Terms,
+		slug,
 		req.Id,
-		req.UserId).Scan(&resp.Id)
+		req.UserId).Scan(&resp.Id, &resp.Slug)
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShop - Failed to update shop")
+		return nil, slugConflict(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateShop - Failed to commit transaction")
 		return nil, err
 	}
 
@@ -120,37 +162,71 @@ func (r *shopRepository) GetShops(ctx context.Context, req *entity.ShopsRequest)
 	type dao struct {
 		TotalData int `db:"total_data"`
 		entity.ShopItem
-		Category string `db:"category_name"`
+		Category  string `db:"category_name"`
+		CursorKey string `db:"cursor_key"`
 	}
 
 	var (
-		resp = new(entity.ShopsResponse)
-		data = make([]dao, 0, req.Paginate)
+		resp       = new(entity.ShopsResponse)
+		data       = make([]dao, 0, req.Paginate+1)
+		totalData  = "COUNT(id) OVER()"
+		nextCursor string
+		args       = []any{req.UserId}
 	)
 	resp.Items = make([]entity.ShopItem, 0, req.Paginate)
 
+	if req.CursorMode {
+		totalData = "0"
+	}
+
 	query := `
 		SELECT
-			COUNT(id) OVER() as total_data,
+			` + totalData + ` as total_data,
+			json_build_array(created_at, id)::text as cursor_key,
 			id,
+			slug,
 			name
 		FROM shops
 		WHERE
 			deleted_at IS NULL
 			AND user_id = ?
-		LIMIT ? OFFSET ?
 	`
 
-	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
-		req.UserId,
-		req.Paginate,
-		req.Paginate*(req.Page-1),
-	)
+	if req.CursorMode && req.Cursor != "" {
+		values, err := decodeListCursor(req.Cursor, shopCursorSort, 2)
+		if err != nil {
+			return nil, err
+		}
+
+		query += " AND (created_at, id) < (?::timestamptz, ?::uuid)"
+		args = append(args, values...)
+	}
+
+	query += " ORDER BY created_at DESC, id DESC"
+
+	if req.CursorMode {
+		query += " LIMIT ?"
+		args = append(args, req.Paginate+1)
+	} else {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, req.Paginate, req.Paginate*(req.Page-1))
+	}
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository::GetShops - Failed to get shops")
 		return nil, err
 	}
 
+	if req.CursorMode && len(data) > req.Paginate {
+		data = data[:req.Paginate]
+		nextCursor, err = encodeListCursor(shopCursorSort, data[len(data)-1].CursorKey)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::GetShops - Failed to encode cursor")
+			return nil, err
+		}
+	}
+
 	if len(data) > 0 {
 		resp.Meta.TotalData = data[0].TotalData
 	}
@@ -161,16 +237,29 @@ func (r *shopRepository) GetShops(ctx context.Context, req *entity.ShopsRequest)
 	}
 
 	var products []entity.ProductItem
+	if len(shopIds) == 0 {
+		if req.CursorMode {
+			resp.Meta.SetCursor(req.Cursor, "", req.Paginate)
+			return resp, nil
+		}
+		resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+		return resp, nil
+	}
+
 	productQuery := `
 		SELECT
 			p.id,
+			p.slug,
 			p.image_url,
 			p.name,
 			p.description,
 			p.price,
+			` + productPrice + ` as effective_price,
 			p.stock,
 			p.user_id,
 			p.shop_id,
+			p.status,
+			p.publish_at,
 			c.name as category
 		FROM products p
 		JOIN categories c ON p.category_id = c.id
@@ -178,7 +267,7 @@ func (r *shopRepository) GetShops(ctx context.Context, req *entity.ShopsRequest)
 			p.shop_id IN (?)
 			AND p.deleted_at IS NULL
 	`
-	query, args, err := sqlx.In(productQuery, shopIds)
+	query, args, err = sqlx.In(productQuery, shopIds)
 	if err != nil {
 		log.Error().Err(err).Msg("repository::GetShops - Failed to construct product query")
 		return nil, err
@@ -193,6 +282,7 @@ func (r *shopRepository) GetShops(ctx context.Context, req *entity.ShopsRequest)
 
 	productMap := make(map[string][]entity.ProductItem)
 	for _, product := range products {
+		product.Status = entity.EffectiveProductStatus(product.Status, product.PublishAt)
 		productMap[product.ShopId] = append(productMap[product.ShopId], product)
 	}
 
@@ -202,20 +292,64 @@ func (r *shopRepository) GetShops(ctx context.Context, req *entity.ShopsRequest)
 		resp.Items = append(resp.Items, shop)
 	}
 
+	if req.CursorMode {
+		resp.Meta.SetCursor(req.Cursor, nextCursor, req.Paginate)
+		return resp, nil
+	}
+
 	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
 
 	return resp, nil
 }
 
 func (r *shopRepository) CreateProduct(ctx context.Context, req *entity.CreateProductRequest) (*entity.CreateProductResponse, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::CreateProduct - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	resp, err := insertProduct(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateProduct - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// insertProduct creates the product together with its image gallery.
+func insertProduct(ctx context.Context, tx *sqlx.Tx, req *entity.CreateProductRequest) (*entity.CreateProductResponse, error) {
 	var resp entity.CreateProductResponse
+
+	slug, err := uniqueProductSlug(ctx, tx, req.ShopId, req.Name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// an unknown merk creates a global brand, see resolveBrand
+	brandId, merk, err := resolveBrand(ctx, tx, req.Merk)
+	if err != nil {
+		return nil, err
+	}
+
+	// the stock of a bundle is derived from its components
+	if req.Type == entity.ProductTypeBundle {
+		req.Stock = 0
+	}
+
 	query := `
-        INSERT INTO products (shop_id, name, description, price, stock, user_id, image_url, category_id, merk, rating)
-        VALUES (?, ?, ?, ?, ?, ?, ?,?,?,?) 
-        RETURNING id, shop_id, name, description, price, stock, user_id, category_id, image_url, merk, rating
+        INSERT INTO products (shop_id, name, description, price, stock, user_id, image_url, category_id, merk, status, publish_at, slug, brand_id, type, weight, length, width, height)
+        VALUES (?, ?, ?, ?, ?, ?, ?,?,?, COALESCE(NULLIF(?, ''), 'published'), ?, ?, ?, COALESCE(NULLIF(?, ''), 'single'), ?, ?, ?, ?) 
+        RETURNING id, slug, shop_id, name, description, price, stock, user_id, category_id, COALESCE(image_url, ''), merk, rating, review_count, status, publish_at, type
     `
 
-	err := r.db.QueryRowContext(ctx, r.db.Rebind(query),
+	err = tx.QueryRowContext(ctx, tx.Rebind(query),
 		req.ShopId,
 		req.Name,
 		req.Description,
@@ -224,14 +358,62 @@ func (r *shopRepository) CreateProduct(ctx context.Context, req *entity.CreatePr
 		req.UserId,
 		req.ImageURL,
 		req.CategoryId,
-		req.Merk,
-		req.Rating,
-	).Scan(&resp.Id, &resp.ShopId, &resp.Name, &resp.Description, &resp.Price, &resp.Stock, &resp.UserId, &resp.CategoryId, &resp.ImageURL, &resp.Merk, &resp.Rating)
+		merk,
+		req.Status,
+		req.PublishAt,
+		slug,
+		brandId,
+		req.Type,
+		req.Weight,
+		req.Length,
+		req.Width,
+		req.Height,
+	).Scan(&resp.Id, &resp.Slug, &resp.ShopId, &resp.Name, &resp.Description, &resp.Price, &resp.Stock, &resp.UserId, &resp.CategoryId, &resp.ImageURL, &resp.Merk, &resp.Rating, &resp.ReviewCount, &resp.Status, &resp.PublishAt, &resp.Type)
 	if err != nil {
-		log.Error().Err(err).Any("payload", req).Msg("repository::CreateProduct - Failed to create product")
+		log.Error().Err(err).Any("payload", req).Msg("repository::insertProduct - Failed to create product")
+		return nil, slugConflict(err)
+	}
+
+	// the single image (if any) leads the gallery, followed by the multi-file upload
+	urls := make([]string, 0, len(req.ImageURLs)+1)
+	if req.ImageURL != "" {
+		urls = append(urls, req.ImageURL)
+	}
+	urls = append(urls, req.ImageURLs...)
+
+	if err := insertProductImages(ctx, tx, resp.Id, urls, true); err != nil {
 		return nil, err
 	}
 
+	resp.Tags, err = setProductTags(ctx, tx, resp.Id, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Type == entity.ProductTypeBundle {
+		if err := setBundleComponents(ctx, tx, resp.Id, resp.ShopId, req.Components); err != nil {
+			return nil, err
+		}
+
+		resp.Components, err = getBundleComponents(ctx, tx, resp.Id, req.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := recordProductRevision(ctx, tx, resp.Id, req.UserId, entity.RevisionActionCreate, "", ""); err != nil {
+		return nil, err
+	}
+
+	if err := recordPriceChange(ctx, tx, resp.Id, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if len(urls) > 0 {
+		resp.ImageURL = urls[0]
+	}
+	resp.Status = entity.EffectiveProductStatus(resp.Status, resp.PublishAt)
+
 	return &resp, nil
 }
 
@@ -239,19 +421,32 @@ func (r *shopRepository) GetProduct(ctx context.Context, req *entity.GetProductR
 	type dao struct {
 		TotalData int    `db:"total_data"`
 		Category  string `db:"category_name"`
+		CursorKey string `db:"cursor_key"`
 		entity.ProductItem
 	}
 
 	var (
-		resp = new(entity.GetProductResponse)
-		data = make([]dao, 0, req.Paginate)
+		resp       = new(entity.GetProductResponse)
+		data       = make([]dao, 0, req.Paginate+1)
+		sort       = productOrder(req)
+		totalData  = "COUNT(p.id) OVER()"
+		nextCursor string
 	)
 	resp.ProductItem = make([]entity.ProductItem, 0, req.Paginate)
 
+	// counting every match is what makes deep pages slow, cursor mode skips it
+	if req.CursorMode {
+		totalData = "0"
+	}
+
+	key, args := sort.key()
+
 	query := `
 		SELECT
-			COUNT(p.id) OVER() as total_data,
+			` + totalData + ` as total_data,
+			` + key + ` as cursor_key,
 			p.id,
+			p.slug,
 			p.image_url,
 			p.name,
 			p.description,
@@ -260,53 +455,132 @@ func (r *shopRepository) GetProduct(ctx context.Context, req *entity.GetProductR
 			p.user_id,
 			p.shop_id,
 			p.rating,
+			p.review_count,
 			p.merk,
-			c.name as category_name
+			p.type,
+			` + productPrice + ` as effective_price,
+			CASE WHEN ` + productOnSale + ` THEN p.sale_ends_at END as sale_ends_at,
+			` + shopCurrency + ` as currency,
+			c.name as category_name,
+			COALESCE(va.min_price, ` + productPrice + `) as min_price,
+			COALESCE(va.max_price, ` + productPrice + `) as max_price,
+			COALESCE(` + bundleStock + `, va.total_stock, p.stock) as total_stock
 		FROM products p
 		JOIN categories c ON p.category_id = c.id
+		LEFT JOIN LATERAL (
+			SELECT
+				MIN(COALESCE(v.price, ` + productPrice + `)) as min_price,
+				MAX(COALESCE(v.price, ` + productPrice + `)) as max_price,
+				SUM(v.stock) as total_stock
+			FROM product_variants v
+			WHERE v.product_id = p.id
+				AND v.deleted_at IS NULL
+		) va ON true
 		WHERE p.deleted_at IS NULL
 	`
 
-	var args []interface{}
-	if req.Keyword != "" {
-		query += " AND p.name ILIKE ?"
-		args = append(args, "%"+req.Keyword+"%")
-	}
-	if req.Category != "" {
-		query += " AND c.name ILIKE ?"
-		args = append(args, "%"+req.Category+"%")
-	}
-	if req.Rating != 0 {
-		query += " AND p.rating = ?"
-		args = append(args, req.Rating)
-	}
+	filter, filterArgs := productFilter(req)
+	query += filter
+	args = append(args, filterArgs...)
 
-	if req.Merk != "" {
-		query += " AND p.merk ILIKE ?"
-		args = append(args, "%"+req.Merk+"%")
+	if req.CursorMode && req.Cursor != "" {
+		values, err := decodeListCursor(req.Cursor, sort.name, len(sort.casts)+1)
+		if err != nil {
+			return nil, err
+		}
+
+		after, afterArgs := sort.after(values)
+		query += after
+		args = append(args, afterArgs...)
 	}
 
-	query += `
+	order, orderArgs := sort.order()
+	query += order
+	args = append(args, orderArgs...)
+
+	if req.CursorMode {
+		// fetch one extra row to know whether there is a next page
+		query += `
+		LIMIT ?
+	`
+		args = append(args, req.Paginate+1)
+	} else {
+		query += `
 		LIMIT ? OFFSET ?
 	`
+		args = append(args, req.Paginate, req.Paginate*(req.Page-1))
+	}
 
-	args = append(args, req.Paginate, req.Paginate*(req.Page-1))
 	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository::GetProduct - Failed to get products")
 		return nil, err
 	}
 
+	if req.CursorMode && len(data) > req.Paginate {
+		data = data[:req.Paginate]
+		nextCursor, err = encodeListCursor(sort.name, data[len(data)-1].CursorKey)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::GetProduct - Failed to encode cursor")
+			return nil, err
+		}
+	}
+
 	if len(data) > 0 {
 		resp.Meta.TotalData = data[0].TotalData
 	}
 
+	productIds := make([]string, len(data))
+	for i, d := range data {
+		productIds[i] = d.Id
+	}
+
+	variants, err := r.getVariantsByProductIds(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := r.getImagesByProductIds(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := r.getTagsByProductIds(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, d := range data {
 		product := d.ProductItem
 		product.Category = d.Category
+		product.Variants = variants[product.Id]
+		if product.Variants == nil {
+			product.Variants = make([]entity.VariantItem, 0)
+		}
+		product.Images = images[product.Id]
+		if product.Images == nil {
+			product.Images = make([]entity.ProductImage, 0)
+		}
+		product.Tags = tags[product.Id]
+		if product.Tags == nil {
+			product.Tags = make([]string, 0)
+		}
 		resp.ProductItem = append(resp.ProductItem, product)
 	}
 
+	// the facets count every match too, the next pages reuse the first one's
+	if !req.CursorMode || req.Cursor == "" {
+		resp.Facets, err = r.getProductFacets(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.CursorMode {
+		resp.Meta.SetCursor(req.Cursor, nextCursor, req.Paginate)
+		return resp, nil
+	}
+
 	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
 
 	return resp, nil
@@ -316,80 +590,252 @@ func (r *shopRepository) GetProductByid(ctx context.Context, req *entity.GetProd
 	var resp = new(entity.GetProductIdResponse)
 	query := `
 		SELECT 
-			id,
-			name,
-			price,
-			stock,
-			shop_id,
-			user_id,
-			image_url,
-			description
-		FROM products
-		WHERE id = ?
-		AND deleted_at IS NULL
+			p.id,
+			p.slug,
+			p.name,
+			p.price,
+			p.stock,
+			p.shop_id,
+			p.user_id,
+			p.category_id,
+			p.image_url,
+			p.description,
+			p.rating,
+			p.review_count,
+			p.status,
+			p.publish_at,
+			p.brand_id,
+			p.type,
+			` + productPrice + ` as effective_price,
+			p.sale_price,
+			p.sale_starts_at,
+			p.sale_ends_at,
+			` + shopCurrency + ` as currency,
+			p.weight,
+			p.length,
+			p.width,
+			p.height,
+			COALESCE(va.min_price, ` + productPrice + `) as min_price,
+			COALESCE(va.max_price, ` + productPrice + `) as max_price,
+			COALESCE(` + bundleStock + `, va.total_stock, p.stock) as total_stock
+		FROM products p
+		LEFT JOIN LATERAL (
+			SELECT
+				MIN(COALESCE(v.price, ` + productPrice + `)) as min_price,
+				MAX(COALESCE(v.price, ` + productPrice + `)) as max_price,
+				SUM(v.stock) as total_stock
+			FROM product_variants v
+			WHERE v.product_id = p.id
+				AND v.deleted_at IS NULL
+		) va ON true
+		WHERE p.id = ?
+		AND p.deleted_at IS NULL
+		AND (` + productVisible + ` OR p.user_id::text = ?)
 	`
 
-	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Id).StructScan(resp)
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Id, req.UserId).StructScan(resp)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
 		log.Error().Err(err).Any("payload", req).Msg("repository::GetProduct - Failed to get product")
 		return nil, err
 	}
+	resp.Status = entity.EffectiveProductStatus(resp.Status, resp.PublishAt)
+	resp.VolumetricWeight = entity.VolumetricWeight(resp.Length, resp.Width, resp.Height)
+
+	variants, err := r.getVariantsByProductIds(ctx, []string{resp.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Variants = variants[resp.Id]
+	if resp.Variants == nil {
+		resp.Variants = make([]entity.VariantItem, 0)
+	}
+
+	images, err := r.getImagesByProductIds(ctx, []string{resp.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Images = images[resp.Id]
+	if resp.Images == nil {
+		resp.Images = make([]entity.ProductImage, 0)
+	}
+
+	resp.Breadcrumbs, err = r.getCategoryBreadcrumbs(ctx, resp.CategoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Attributes, err = r.getProductAttributes(ctx, r.db, resp.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := r.getTagsByProductIds(ctx, []string{resp.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Tags = tags[resp.Id]
+	if resp.Tags == nil {
+		resp.Tags = make([]string, 0)
+	}
+
+	if resp.Type == entity.ProductTypeBundle {
+		resp.Components, err = getBundleComponents(ctx, r.db, resp.Id, req.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp.PriceTiers, err = getPriceTiers(ctx, r.db, resp.Id)
+	if err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
 func (r *shopRepository) UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (*entity.UpdateProductResponse, error) {
 	var resp = new(entity.UpdateProductResponse)
 
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::UpdateProduct - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	before, err := getProductSnapshot(ctx, tx, req.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	slug, err := reslugProduct(ctx, tx, req.Id, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE products
-		SET name = ?, description = ?, price = ?, stock = ?, 
-		    image_url = COALESCE(NULLIF(?, ''), image_url), 
+		SET name = ?, description = ?, price = ?, slug = ?,
+		    stock = CASE WHEN type = 'bundle' THEN 0 ELSE ? END,
+		    weight = COALESCE(?, weight),
+		    length = COALESCE(?, length),
+		    width = COALESCE(?, width),
+		    height = COALESCE(?, height),
 		    updated_at = NOW()
 		WHERE id = ? AND user_id = ?
-		RETURNING id
+		RETURNING id, slug
 	`
-	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query),
 		req.Name,
 		req.Description,
 		req.Price,
+		slug,
 		req.Stock,
-		req.ImageURL,
+		req.Weight,
+		req.Length,
+		req.Width,
+		req.Height,
 		req.Id,
-		req.UserId).Scan(&resp.Id)
+		req.UserId).Scan(&resp.Id, &resp.Slug)
 
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateProduct - Failed to update product")
+		return nil, slugConflict(err)
+	}
+
+	// a newly uploaded single image replaces the primary image, extra uploads are appended
+	if req.ImageURL != "" {
+		if err := insertProductImages(ctx, tx, resp.Id, []string{req.ImageURL}, true); err != nil {
+			return nil, err
+		}
+	}
+	if err := insertProductImages(ctx, tx, resp.Id, req.ImageURLs, false); err != nil {
 		return nil, err
 	}
+
+	if req.Tags != nil {
+		if _, err := setProductTags(ctx, tx, resp.Id, *req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := recordProductRevision(ctx, tx, resp.Id, req.UserId, entity.RevisionActionUpdate, before, ""); err != nil {
+		return nil, err
+	}
+
+	if err := recordPriceChange(ctx, tx, resp.Id, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateProduct - Failed to commit transaction")
+		return nil, err
+	}
+
 	return resp, nil
 }
 
 func (r *shopRepository) DeleteProduct(ctx context.Context, req *entity.DeleteProductRequest) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::DeleteProduct - Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getProductSnapshot(ctx, tx, req.Id, req.UserId)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE products
 		SET deleted_at = NOW()
-		WHERE id = ? AND user_id = ?
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
 	`
 
-	_, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.UserId)
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Id, req.UserId)
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteProduct - Failed to delete product")
 		return err
 	}
 
+	if _, err := recordProductRevision(ctx, tx, req.Id, req.UserId, entity.RevisionActionDelete, before, ""); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteProduct - Failed to commit transaction")
+		return err
+	}
+
 	log.Info().Msg("repository::DeleteProduct - Product marked as deleted successfully")
 	return nil
 }
 
 func (r *shopRepository) CreateCategory(ctx context.Context, req *entity.CreateCategoryRequest) (*entity.CreateCategoryResponse, error) {
 	var resp = new(entity.CreateCategoryResponse)
+
+	if req.ParentId != "" {
+		if err := r.checkCategoryParent(ctx, r.db, req.UserId, "", req.ParentId); err != nil {
+			return nil, err
+		}
+	}
+
 	query := `
-        INSERT INTO categories (name , user_id )
-        VALUES (?, ?) RETURNING id, name, user_id
+        INSERT INTO categories (name , user_id, parent_id)
+        VALUES (?, ?, NULLIF(?, '')::uuid) RETURNING id, name, user_id, parent_id
     `
 	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
 		req.Name,
 		req.UserId,
+		req.ParentId,
 	).StructScan(resp)
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository::CreateCategory - Failed to create category")
@@ -404,7 +850,7 @@ func (r *shopRepository) GetCategory(ctx context.Context, req *entity.GetCategor
 		resp  = &entity.GetCategoryResponse{}
 	)
 	query := `
-		SELECT id, name, user_id
+		SELECT id, name, user_id, parent_id
 		FROM categories
 		WHERE user_id = ?
 		AND deleted_at IS NULL
@@ -424,13 +870,13 @@ func (r *shopRepository) GetCategoryId(ctx context.Context, req *entity.GetCateg
 	var resp entity.GetcategoryIdResponse
 
 	query := `
-        SELECT id, name, user_id
+        SELECT id, name, user_id, parent_id
         FROM categories
         WHERE id = ?
 		AND deleted_at IS NULL
     `
 
-	err := r.db.QueryRowContext(ctx, query, req.Id).Scan(&resp.Id, &resp.Name, &resp.UserId)
+	err := r.db.QueryRowContext(ctx, r.db.Rebind(query), req.Id).Scan(&resp.Id, &resp.Name, &resp.UserId, &resp.ParentId)
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository::GetCategoryId - Failed to get category")
 		return nil, err
@@ -440,6 +886,80 @@ func (r *shopRepository) GetCategoryId(ctx context.Context, req *entity.GetCateg
 }
 
 func (r *shopRepository) DeletCategory(ctx context.Context, req *entity.DeleteCategoryRequest) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::DeleteCategory - Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.GetContext(ctx, &id, tx.Rebind(`
+		SELECT id FROM categories
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		FOR UPDATE
+	`), req.Id, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Err(err).Any("payload", req).Msg("repository::DeleteCategory - Category not found")
+			return errmsg.NewCustomErrors(404, errmsg.WithMessage("Kategori tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteCategory - Failed to get category")
+		return err
+	}
+
+	var children int
+	err = tx.GetContext(ctx, &children, tx.Rebind(`
+		SELECT COUNT(*) FROM categories
+		WHERE parent_id = ? AND deleted_at IS NULL
+	`), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteCategory - Failed to count sub categories")
+		return err
+	}
+	if children > 0 {
+		return errmsg.NewCustomErrors(409, errmsg.WithMessage("Kategori masih memiliki sub kategori"))
+	}
+
+	if req.ReassignTo != "" {
+		var exists bool
+		err = tx.GetContext(ctx, &exists, tx.Rebind(`
+			SELECT EXISTS (
+				SELECT 1 FROM categories
+				WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+			)
+		`), req.ReassignTo, req.UserId)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::DeleteCategory - Failed to check target category")
+			return err
+		}
+		if !exists {
+			return errmsg.NewCustomErrors(404, errmsg.WithMessage("Kategori tujuan tidak ditemukan"))
+		}
+
+		_, err = tx.ExecContext(ctx, tx.Rebind(`
+			UPDATE products
+			SET category_id = ?, updated_at = NOW()
+			WHERE category_id = ? AND deleted_at IS NULL
+		`), req.ReassignTo, req.Id)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::DeleteCategory - Failed to reassign products")
+			return err
+		}
+	} else {
+		var products int
+		err = tx.GetContext(ctx, &products, tx.Rebind(`
+			SELECT COUNT(*) FROM products
+			WHERE category_id = ? AND deleted_at IS NULL
+		`), req.Id)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::DeleteCategory - Failed to count products")
+			return err
+		}
+		if products > 0 {
+			return errmsg.NewCustomErrors(409, errmsg.WithMessage("Kategori masih digunakan oleh produk"))
+		}
+	}
 
 	query := `
 		UPDATE categories
@@ -447,12 +967,17 @@ func (r *shopRepository) DeletCategory(ctx context.Context, req *entity.DeleteCa
 		WHERE id = ? AND user_id = ?
 	`
 
-	_, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.UserId)
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Id, req.UserId)
 	if err != nil {
 		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteCategory - Failed to delete category")
 		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteCategory - Failed to commit transaction")
+		return err
+	}
+
 	log.Info().Msg("repository::DeleteCategory - Category marked as deleted successfully")
 	return nil
 }
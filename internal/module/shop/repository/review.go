@@ -0,0 +1,311 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+func (r *shopRepository) GetReviews(ctx context.Context, req *entity.GetReviewsRequest) (*entity.GetReviewsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.ReviewItem
+		PhotoURLs pq.StringArray `db:"photo_urls"`
+	}
+
+	var (
+		resp = new(entity.GetReviewsResponse)
+		data = make([]dao, 0, req.Paginate)
+		args = []any{req.ProductId}
+	)
+	resp.Items = make([]entity.ReviewItem, 0, req.Paginate)
+
+	err := r.db.GetContext(ctx, resp, r.db.Rebind(`
+		SELECT p.rating, p.review_count FROM products p
+		WHERE p.id = ?
+			AND p.deleted_at IS NULL
+			AND (`+productVisible+` OR p.user_id::text = ?)
+	`), req.ProductId, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetReviews - Failed to get product rating")
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			COUNT(id) OVER() as total_data,
+			id,
+			product_id,
+			user_id,
+			rating,
+			body,
+			photo_urls,
+			seller_reply,
+			replied_at,
+			created_at,
+			updated_at
+		FROM product_reviews
+		WHERE
+			deleted_at IS NULL
+			AND product_id = ?
+	`
+
+	if req.Rating > 0 {
+		query += " AND rating = ?"
+		args = append(args, req.Rating)
+	}
+
+	query += " ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?"
+	args = append(args, req.Paginate, req.Paginate*(req.Page-1))
+
+	err = r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetReviews - Failed to get reviews")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		d.ReviewItem.Photos = []string(d.PhotoURLs)
+		resp.Items = append(resp.Items, d.ReviewItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *shopRepository) CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.CreateReviewResponse, error) {
+	var resp = new(entity.CreateReviewResponse)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::CreateReview - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var ownerId string
+	err = tx.GetContext(ctx, &ownerId, tx.Rebind(`
+		SELECT p.user_id FROM products p
+		WHERE p.id = ? AND p.deleted_at IS NULL AND `+productVisible+`
+	`), req.ProductId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateReview - Failed to get product")
+		return nil, err
+	}
+
+	if ownerId == req.UserId {
+		return nil, errmsg.NewCustomErrors(403, errmsg.WithMessage("Anda tidak dapat mengulas produk milik sendiri"))
+	}
+
+	photos := req.PhotoURLs
+	if photos == nil {
+		photos = make([]string, 0)
+	}
+
+	query := `
+		INSERT INTO product_reviews (product_id, user_id, rating, body, photo_urls)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query),
+		req.ProductId,
+		req.UserId,
+		req.Rating,
+		req.Body,
+		pq.Array(photos),
+	).Scan(&resp.Id)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			return nil, errmsg.NewCustomErrors(409, errmsg.WithMessage("Anda sudah memberikan ulasan untuk produk ini"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateReview - Failed to insert review")
+		return nil, err
+	}
+
+	if err := refreshProductRating(ctx, tx, req.ProductId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateReview - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) (*entity.UpdateReviewResponse, error) {
+	var resp = new(entity.UpdateReviewResponse)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::UpdateReview - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// photos are only replaced when new ones are uploaded
+	query := `
+		UPDATE product_reviews
+		SET
+			rating = ?,
+			body = ?,
+			photo_urls = COALESCE(?, photo_urls),
+			updated_at = NOW()
+		WHERE
+			id = ?
+			AND product_id = ?
+			AND user_id = ?
+			AND deleted_at IS NULL
+		RETURNING id
+	`
+
+	var photos any
+	if len(req.PhotoURLs) > 0 {
+		photos = pq.Array(req.PhotoURLs)
+	}
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query),
+		req.Rating,
+		req.Body,
+		photos,
+		req.Id,
+		req.ProductId,
+		req.UserId,
+	).Scan(&resp.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Ulasan tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateReview - Failed to update review")
+		return nil, err
+	}
+
+	if err := refreshProductRating(ctx, tx, req.ProductId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateReview - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::DeleteReview - Failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE product_reviews
+		SET deleted_at = NOW()
+		WHERE
+			id = ?
+			AND product_id = ?
+			AND user_id = ?
+			AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), req.Id, req.ProductId, req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteReview - Failed to delete review")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteReview - Failed to get affected rows")
+		return err
+	}
+	if affected == 0 {
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Ulasan tidak ditemukan"))
+	}
+
+	if err := refreshProductRating(ctx, tx, req.ProductId); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteReview - Failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *shopRepository) ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) (*entity.ReplyReviewResponse, error) {
+	var resp = new(entity.ReplyReviewResponse)
+
+	if err := r.checkProductOwner(ctx, r.db, req.ProductId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE product_reviews
+		SET seller_reply = ?, replied_at = NOW()
+		WHERE
+			id = ?
+			AND product_id = ?
+			AND deleted_at IS NULL
+		RETURNING id
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query), req.Reply, req.Id, req.ProductId).Scan(&resp.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Ulasan tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::ReplyReview - Failed to reply review")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// refreshProductRating recomputes the average rating and the review count of
+// the product from its active reviews.
+func refreshProductRating(ctx context.Context, tx *sqlx.Tx, productId string) error {
+	query := `
+		UPDATE products
+		SET
+			rating = COALESCE((
+				SELECT ROUND(AVG(rating), 2) FROM product_reviews
+				WHERE product_id = ? AND deleted_at IS NULL
+			), 0),
+			review_count = (
+				SELECT COUNT(*) FROM product_reviews
+				WHERE product_id = ? AND deleted_at IS NULL
+			)
+		WHERE id = ?
+	`
+
+	_, err := tx.ExecContext(ctx, tx.Rebind(query), productId, productId, productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::refreshProductRating - Failed to refresh rating")
+		return err
+	}
+
+	return nil
+}
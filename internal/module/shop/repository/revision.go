@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// productSnapshot is the document kept in a revision, its keys follow
+// entity.ProductRevisionFields. It expects products to be aliased as p.
+const productSnapshot = `jsonb_build_object(
+	'name', p.name,
+	'description', p.description,
+	'price', p.price,
+	'stock', p.stock,
+	'merk', p.merk,
+	'categoryId', p.category_id,
+	'imageUrl', p.image_url,
+	'status', p.status,
+	'publishAt', p.publish_at,
+	'deletedAt', p.deleted_at,
+	'weight', p.weight,
+	'length', p.length,
+	'width', p.width,
+	'height', p.height,
+	'tags', (
+		SELECT COALESCE(jsonb_agg(t.name ORDER BY t.name), '[]'::jsonb)
+		FROM product_tags pt
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.product_id = p.id
+	)
+)`
+
+func (r *shopRepository) GetProductRevisions(ctx context.Context, req *entity.GetProductRevisionsRequest) (*entity.GetProductRevisionsResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.ProductRevision
+		Before string `db:"before"`
+		After  string `db:"after"`
+	}
+
+	var (
+		resp = new(entity.GetProductRevisionsResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.ProductRevision, 0, req.Paginate)
+
+	if err := r.checkProductOwner(ctx, r.db, req.ProductId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			COUNT(id) OVER() as total_data,
+			id,
+			product_id,
+			user_id,
+			action,
+			rollback_of,
+			created_at,
+			COALESCE(before::text, '') as before,
+			after::text as after
+		FROM product_revisions
+		WHERE product_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), req.ProductId, req.Paginate, req.Paginate*(req.Page-1))
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetProductRevisions - Failed to get revisions")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		d.ProductRevision.Changes, err = diffProductSnapshots(d.Before, d.After)
+		if err != nil {
+			log.Error().Err(err).Str("revision_id", d.Id).Msg("repository::GetProductRevisions - Failed to diff revision")
+			return nil, err
+		}
+		resp.Items = append(resp.Items, d.ProductRevision)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+// RollbackProduct puts the content of the product (name, description, price,
+// stock, merk, category, shipping attributes and tags) back to the state right
+// after the revision. The status and the gallery have their own endpoints and
+// are left untouched. Revisions older than the shipping attributes or the tags
+// leave those as they are.
+func (r *shopRepository) RollbackProduct(ctx context.Context, req *entity.RollbackProductRequest) (*entity.RollbackProductResponse, error) {
+	var (
+		resp     = &entity.RollbackProductResponse{Id: req.ProductId}
+		snapshot string
+		target   struct {
+			Name        string      `json:"name"`
+			Description string      `json:"description"`
+			Price       types.Money `json:"price"`
+			Stock       int         `json:"stock"`
+			Merk        string      `json:"merk"`
+			CategoryId  string      `json:"categoryId"`
+			Weight      *int        `json:"weight"`
+			Length      *int        `json:"length"`
+			Width       *int        `json:"width"`
+			Height      *int        `json:"height"`
+			Tags        *[]string   `json:"tags"`
+		}
+		fields map[string]json.RawMessage
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::RollbackProduct - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	before, err := getProductSnapshot(ctx, tx, req.ProductId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.GetContext(ctx, &snapshot, tx.Rebind(`
+		SELECT after::text FROM product_revisions WHERE id = ? AND product_id = ?
+	`), req.RevisionId, req.ProductId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Revisi tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::RollbackProduct - Failed to get revision")
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(snapshot), &target); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::RollbackProduct - Failed to decode revision")
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(snapshot), &fields); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::RollbackProduct - Failed to decode revision")
+		return nil, err
+	}
+
+	var categoryExists bool
+	err = tx.GetContext(ctx, &categoryExists, tx.Rebind(`
+		SELECT EXISTS (SELECT 1 FROM categories WHERE id = ? AND deleted_at IS NULL)
+	`), target.CategoryId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::RollbackProduct - Failed to check category")
+		return nil, err
+	}
+	if !categoryExists {
+		return nil, errmsg.NewCustomErrors(409,
+			errmsg.WithMessage("Revisi tidak dapat dipulihkan"),
+			errmsg.WithErrors("categoryId", "kategori pada revisi ini sudah dihapus."),
+		)
+	}
+
+	// the name coming back moves the slug along like a rename does
+	slug, err := reslugProduct(ctx, tx, req.ProductId, target.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	brandId, merk, err := resolveBrand(ctx, tx, target.Merk)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE products
+		SET name = ?, description = ?, price = ?, stock = ?, merk = ?, brand_id = ?, category_id = ?, slug = ?, updated_at = NOW()
+	`
+	args := []any{
+		target.Name,
+		target.Description,
+		target.Price,
+		target.Stock,
+		merk,
+		brandId,
+		target.CategoryId,
+		slug,
+	}
+
+	if _, ok := fields["weight"]; ok {
+		query += ", weight = ?, length = ?, width = ?, height = ?"
+		args = append(args, target.Weight, target.Length, target.Width, target.Height)
+	}
+
+	query += " WHERE id = ? AND deleted_at IS NULL"
+	args = append(args, req.ProductId)
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::RollbackProduct - Failed to update product")
+		return nil, slugConflict(err)
+	}
+
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+	}
+
+	if target.Tags != nil {
+		if _, err := setProductTags(ctx, tx, req.ProductId, *target.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	resp.RevisionId, err = recordProductRevision(ctx, tx, req.ProductId, req.UserId, entity.RevisionActionRollback, before, req.RevisionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordPriceChange(ctx, tx, req.ProductId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::RollbackProduct - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// getProductSnapshot locks the product of the owner and returns its current
+// snapshot, to be passed as the before state of recordProductRevision.
+func getProductSnapshot(ctx context.Context, tx *sqlx.Tx, productId, userId string) (string, error) {
+	var snapshot string
+
+	err := tx.GetContext(ctx, &snapshot, tx.Rebind(`
+		SELECT `+productSnapshot+`::text
+		FROM products p
+		WHERE p.id = ? AND p.user_id = ?
+		FOR UPDATE
+	`), productId, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::getProductSnapshot - Failed to get snapshot")
+		return "", err
+	}
+
+	return snapshot, nil
+}
+
+// recordProductRevision stores the change from before (empty for a new
+// product) to the current state of the product. Nothing is stored when the
+// tracked fields did not change, the returned id is empty then.
+func recordProductRevision(ctx context.Context, tx *sqlx.Tx, productId, userId, action, before, rollbackOf string) (string, error) {
+	var id string
+
+	query := `
+		INSERT INTO product_revisions (product_id, user_id, action, before, after, rollback_of)
+		SELECT p.id, ?, ?, NULLIF(?, '')::jsonb, ` + productSnapshot + `, NULLIF(?, '')::uuid
+		FROM products p
+		WHERE p.id = ?
+			AND NULLIF(?, '')::jsonb IS DISTINCT FROM ` + productSnapshot + `
+		RETURNING id
+	`
+
+	err := tx.GetContext(ctx, &id, tx.Rebind(query), userId, action, before, rollbackOf, productId, before)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::recordProductRevision - Failed to record revision")
+		return "", err
+	}
+
+	return id, nil
+}
+
+// diffProductSnapshots lists the fields that differ between two snapshots, an
+// empty before reports every field of after as new.
+func diffProductSnapshots(before, after string) ([]entity.FieldChange, error) {
+	var (
+		changes  = make([]entity.FieldChange, 0)
+		beforeKV = make(map[string]any)
+		afterKV  = make(map[string]any)
+	)
+
+	decode := func(doc string, v *map[string]any) error {
+		if doc == "" {
+			return nil
+		}
+		dec := json.NewDecoder(strings.NewReader(doc))
+		dec.UseNumber()
+		return dec.Decode(v)
+	}
+
+	if err := decode(before, &beforeKV); err != nil {
+		return nil, err
+	}
+	if err := decode(after, &afterKV); err != nil {
+		return nil, err
+	}
+
+	for _, field := range entity.ProductRevisionFields {
+		b, a := beforeKV[field], afterKV[field]
+		if before == "" && a == nil {
+			continue
+		}
+		if !reflect.DeepEqual(b, a) {
+			changes = append(changes, entity.FieldChange{Field: field, Before: b, After: a})
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+const productSaleColumns = `
+	p.id,
+	p.price,
+	` + productPrice + ` as effective_price,
+	p.sale_price,
+	p.sale_starts_at,
+	p.sale_ends_at
+`
+
+func (r *shopRepository) SetProductSale(ctx context.Context, req *entity.SetProductSaleRequest) (*entity.ProductSaleResponse, error) {
+	var (
+		resp  = new(entity.ProductSaleResponse)
+		price types.Money
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::SetProductSale - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.GetContext(ctx, &price, tx.Rebind(`
+		SELECT price FROM products
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		FOR UPDATE
+	`), req.ProductId, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetProductSale - Failed to get product")
+		return nil, err
+	}
+
+	if req.SalePrice.Amount >= price.Amount {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Harga diskon tidak valid"),
+			errmsg.WithErrors("salePrice", "salePrice harus kurang dari harga produk."),
+		)
+	}
+
+	err = tx.GetContext(ctx, resp, tx.Rebind(`
+		UPDATE products p
+		SET sale_price = ?, sale_starts_at = ?, sale_ends_at = ?, updated_at = NOW()
+		WHERE p.id = ?
+		RETURNING `+productSaleColumns), req.SalePrice, req.StartsAt, req.EndsAt, req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetProductSale - Failed to set sale")
+		return nil, err
+	}
+
+	if err := recordPriceChange(ctx, tx, req.ProductId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetProductSale - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) DeleteProductSale(ctx context.Context, req *entity.DeleteProductSaleRequest) (*entity.ProductSaleResponse, error) {
+	var resp = new(entity.ProductSaleResponse)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::DeleteProductSale - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.GetContext(ctx, resp, tx.Rebind(`
+		UPDATE products p
+		SET sale_price = NULL, sale_starts_at = NULL, sale_ends_at = NULL, updated_at = NOW()
+		WHERE p.id = ? AND p.user_id = ? AND p.deleted_at IS NULL
+		RETURNING `+productSaleColumns), req.ProductId, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteProductSale - Failed to delete sale")
+		return nil, err
+	}
+
+	if err := recordPriceChange(ctx, tx, req.ProductId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteProductSale - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (*entity.GetPriceHistoryResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.PriceHistoryItem
+	}
+
+	var (
+		resp    = new(entity.GetPriceHistoryResponse)
+		data    = make([]dao, 0, req.Paginate)
+		visible bool
+	)
+	resp.Items = make([]entity.PriceHistoryItem, 0, req.Paginate)
+
+	err := r.db.GetContext(ctx, &visible, r.db.Rebind(`
+		SELECT EXISTS (
+			SELECT 1 FROM products p
+			WHERE p.id = ? AND p.deleted_at IS NULL
+				AND (`+productVisible+` OR p.user_id::text = ?)
+		)
+	`), req.ProductId, req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetPriceHistory - Failed to check product")
+		return nil, err
+	}
+	if !visible {
+		return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+	}
+
+	err = r.db.SelectContext(ctx, &data, r.db.Rebind(`
+		SELECT
+			COUNT(id) OVER() as total_data,
+			id,
+			price,
+			sale_price,
+			sale_starts_at,
+			sale_ends_at,
+			created_at
+		FROM product_price_history
+		WHERE product_id = ?
+		ORDER BY created_at DESC, id
+		LIMIT ? OFFSET ?
+	`), req.ProductId, req.Paginate, req.Paginate*(req.Page-1))
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetPriceHistory - Failed to get price history")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.PriceHistoryItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+// recordPriceChange is called after the price or the sale of a product may
+// have changed. A sale that is not below the price anymore is dropped, then
+// the price and sale are added to the history when they differ from its last
+// entry.
+func recordPriceChange(ctx context.Context, tx *sqlx.Tx, productId, userId string) error {
+	_, err := tx.ExecContext(ctx, tx.Rebind(`
+		UPDATE products
+		SET sale_price = NULL, sale_starts_at = NULL, sale_ends_at = NULL
+		WHERE id = ? AND sale_price >= price
+	`), productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::recordPriceChange - Failed to drop sale")
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		INSERT INTO product_price_history (product_id, user_id, price, sale_price, sale_starts_at, sale_ends_at)
+		SELECT p.id, NULLIF(?, '')::uuid, p.price, p.sale_price, p.sale_starts_at, p.sale_ends_at
+		FROM products p
+		WHERE p.id = ?
+			AND NOT EXISTS (
+				SELECT 1 FROM (
+					SELECT * FROM product_price_history
+					WHERE product_id = p.id
+					ORDER BY created_at DESC, id
+					LIMIT 1
+				) h
+				WHERE h.price = p.price
+					AND h.sale_price IS NOT DISTINCT FROM p.sale_price
+					AND h.sale_starts_at IS NOT DISTINCT FROM p.sale_starts_at
+					AND h.sale_ends_at IS NOT DISTINCT FROM p.sale_ends_at
+			)
+	`), userId, productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::recordPriceChange - Failed to record price history")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"cmp"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// slugTarget is the row a slug, current or old, resolves to.
+type slugTarget struct {
+	Id   string `db:"id"`
+	Slug string `db:"slug"`
+}
+
+func (r *shopRepository) GetShopBySlug(ctx context.Context, req *entity.GetShopBySlugRequest) (*entity.GetShopBySlugResponse, error) {
+	var (
+		resp   = new(entity.GetShopBySlugResponse)
+		target slugTarget
+	)
+
+	err := r.db.GetContext(ctx, &target, r.db.Rebind(`
+		SELECT id, slug FROM shops WHERE slug = ? AND deleted_at IS NULL
+	`), req.Slug)
+	if err == nil {
+		resp.Id, resp.Slug = target.Id, target.Slug
+		resp.GetShopResponse, err = r.GetShop(ctx, &entity.GetShopRequest{Id: target.Id})
+		if err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+	if err != sql.ErrNoRows {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetShopBySlug - Failed to get shop")
+		return nil, err
+	}
+
+	err = r.db.GetContext(ctx, &target, r.db.Rebind(`
+		SELECT s.id, s.slug
+		FROM shop_slug_redirects sr
+		JOIN shops s ON s.id = sr.shop_id
+		WHERE sr.old_slug = ? AND s.deleted_at IS NULL
+	`), req.Slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetShopBySlug - Failed to get redirect")
+		return nil, err
+	}
+
+	resp.Id, resp.Slug, resp.Redirected = target.Id, target.Slug, true
+
+	return resp, nil
+}
+
+func (r *shopRepository) GetProductBySlug(ctx context.Context, req *entity.GetProductBySlugRequest) (*entity.GetProductBySlugResponse, error) {
+	var (
+		resp   = new(entity.GetProductBySlugResponse)
+		target slugTarget
+	)
+
+	shop, err := r.GetShopBySlug(ctx, &entity.GetShopBySlugRequest{Slug: req.ShopSlug})
+	if err != nil {
+		return nil, err
+	}
+	resp.ShopSlug = shop.Slug
+
+	err = r.db.GetContext(ctx, &target, r.db.Rebind(`
+		SELECT p.id, p.slug FROM products p
+		WHERE p.shop_id = ? AND p.slug = ?
+			AND p.deleted_at IS NULL
+			AND (`+productVisible+` OR p.user_id::text = ?)
+	`), shop.Id, req.Slug, req.UserId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetProductBySlug - Failed to get product")
+		return nil, err
+	}
+
+	if err == sql.ErrNoRows {
+		err = r.db.GetContext(ctx, &target, r.db.Rebind(`
+			SELECT p.id, p.slug
+			FROM product_slug_redirects pr
+			JOIN products p ON p.id = pr.product_id
+			WHERE pr.shop_id = ? AND pr.old_slug = ?
+				AND p.deleted_at IS NULL
+				AND (`+productVisible+` OR p.user_id::text = ?)
+		`), shop.Id, req.Slug, req.UserId)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+			}
+			log.Error().Err(err).Any("payload", req).Msg("repository::GetProductBySlug - Failed to get redirect")
+			return nil, err
+		}
+		resp.Redirected = true
+	}
+
+	resp.Id, resp.Slug = target.Id, target.Slug
+
+	if shop.Redirected || resp.Redirected {
+		resp.Redirected = true
+		return resp, nil
+	}
+
+	resp.GetProductIdResponse, err = r.GetProductByid(ctx, &entity.GetProductIdRequest{Id: target.Id, UserId: req.UserId})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// uniqueShopSlug returns the slug for a shop named name. The current slug of
+// the shop itself (shopId, empty for a new shop) and its old slugs may be
+// taken again, the slugs of other shops, old ones included, may not.
+func uniqueShopSlug(ctx context.Context, tx *sqlx.Tx, name, shopId string) (string, error) {
+	var (
+		base  = cmp.Or(pkg.Slugify(name), "toko")
+		taken = make([]string, 0)
+	)
+
+	err := tx.SelectContext(ctx, &taken, tx.Rebind(`
+		SELECT slug FROM shops
+		WHERE (slug = ? OR slug ~ ?) AND id IS DISTINCT FROM NULLIF(?, '')::uuid
+		UNION
+		SELECT old_slug FROM shop_slug_redirects
+		WHERE (old_slug = ? OR old_slug ~ ?) AND shop_id IS DISTINCT FROM NULLIF(?, '')::uuid
+	`), base, slugSuffixPattern(base), shopId, base, slugSuffixPattern(base), shopId)
+	if err != nil {
+		log.Error().Err(err).Str("name", name).Msg("repository::uniqueShopSlug - Failed to get taken slugs")
+		return "", err
+	}
+
+	return pickSlug(base, taken), nil
+}
+
+// uniqueProductSlug is uniqueShopSlug for a product, slugs only have to be
+// unique within the shop.
+func uniqueProductSlug(ctx context.Context, tx *sqlx.Tx, shopId, name, productId string) (string, error) {
+	var (
+		base  = cmp.Or(pkg.Slugify(name), "produk")
+		taken = make([]string, 0)
+	)
+
+	err := tx.SelectContext(ctx, &taken, tx.Rebind(`
+		SELECT slug FROM products
+		WHERE shop_id = ? AND (slug = ? OR slug ~ ?) AND id IS DISTINCT FROM NULLIF(?, '')::uuid
+		UNION
+		SELECT old_slug FROM product_slug_redirects
+		WHERE shop_id = ? AND (old_slug = ? OR old_slug ~ ?) AND product_id IS DISTINCT FROM NULLIF(?, '')::uuid
+	`), shopId, base, slugSuffixPattern(base), productId, shopId, base, slugSuffixPattern(base), productId)
+	if err != nil {
+		log.Error().Err(err).Str("shop_id", shopId).Str("name", name).Msg("repository::uniqueProductSlug - Failed to get taken slugs")
+		return "", err
+	}
+
+	return pickSlug(base, taken), nil
+}
+
+// slugSuffixPattern matches base followed by a numeric suffix, a slug only
+// holds characters that are literal in a regular expression.
+func slugSuffixPattern(base string) string {
+	return "^" + base + "-[0-9]+$"
+}
+
+// pickSlug returns base, or base with the lowest free suffix from 2 on.
+func pickSlug(base string, taken []string) string {
+	used := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		used[s] = true
+	}
+
+	slug := base
+	for i := 2; used[slug]; i++ {
+		slug = base + "-" + strconv.Itoa(i)
+	}
+
+	return slug
+}
+
+// reslugShop locks the shop of the owner and returns the slug it should have
+// when named name. A changed slug leaves a redirect from the old one behind.
+func reslugShop(ctx context.Context, tx *sqlx.Tx, shopId, userId, name string) (string, error) {
+	var current struct {
+		Name string `db:"name"`
+		Slug string `db:"slug"`
+	}
+
+	err := tx.GetContext(ctx, &current, tx.Rebind(`
+		SELECT name, slug FROM shops WHERE id = ? AND user_id = ? FOR UPDATE
+	`), shopId, userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errmsg.NewCustomErrors(404, errmsg.WithMessage("Toko tidak ditemukan"))
+		}
+		log.Error().Err(err).Str("shop_id", shopId).Msg("repository::reslugShop - Failed to get shop")
+		return "", err
+	}
+
+	if current.Name == name {
+		return current.Slug, nil
+	}
+
+	slug, err := uniqueShopSlug(ctx, tx, name, shopId)
+	if err != nil || slug == current.Slug {
+		return slug, err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		INSERT INTO shop_slug_redirects (old_slug, shop_id) VALUES (?, ?)
+		ON CONFLICT (old_slug) DO UPDATE SET shop_id = EXCLUDED.shop_id, created_at = NOW()
+	`), current.Slug, shopId)
+	if err != nil {
+		log.Error().Err(err).Str("shop_id", shopId).Msg("repository::reslugShop - Failed to record redirect")
+		return "", err
+	}
+
+	// the shop takes back one of its old slugs
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		DELETE FROM shop_slug_redirects WHERE old_slug = ?
+	`), slug)
+	if err != nil {
+		log.Error().Err(err).Str("shop_id", shopId).Msg("repository::reslugShop - Failed to delete redirect")
+		return "", err
+	}
+
+	return slug, nil
+}
+
+// reslugProduct is reslugShop for a product, the caller has already locked
+// the product and checked its owner.
+func reslugProduct(ctx context.Context, tx *sqlx.Tx, productId, name string) (string, error) {
+	var current struct {
+		ShopId string `db:"shop_id"`
+		Name   string `db:"name"`
+		Slug   string `db:"slug"`
+	}
+
+	err := tx.GetContext(ctx, &current, tx.Rebind(`
+		SELECT shop_id, name, slug FROM products WHERE id = ?
+	`), productId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::reslugProduct - Failed to get product")
+		return "", err
+	}
+
+	if current.Name == name {
+		return current.Slug, nil
+	}
+
+	slug, err := uniqueProductSlug(ctx, tx, current.ShopId, name, productId)
+	if err != nil || slug == current.Slug {
+		return slug, err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		INSERT INTO product_slug_redirects (shop_id, old_slug, product_id) VALUES (?, ?, ?)
+		ON CONFLICT (shop_id, old_slug) DO UPDATE SET product_id = EXCLUDED.product_id, created_at = NOW()
+	`), current.ShopId, current.Slug, productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::reslugProduct - Failed to record redirect")
+		return "", err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		DELETE FROM product_slug_redirects WHERE shop_id = ? AND old_slug = ?
+	`), current.ShopId, slug)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::reslugProduct - Failed to delete redirect")
+		return "", err
+	}
+
+	return slug, nil
+}
+
+// slugConflict turns a concurrent insert of the same slug into a 409, the
+// request can simply be retried.
+func slugConflict(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+		switch pqErr.Constraint {
+		case "shops_slug_idx", "products_shop_id_slug_idx":
+			return errmsg.NewCustomErrors(409, errmsg.WithMessage("Slug sedang digunakan, silakan coba lagi"))
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (r *shopRepository) UpdateProductStatus(ctx context.Context, req *entity.UpdateProductStatusRequest) (*entity.UpdateProductStatusResponse, error) {
+	var (
+		resp    = new(entity.UpdateProductStatusResponse)
+		current string
+	)
+
+	if req.PublishAt != nil && req.Status != entity.ProductStatusPublished {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Jadwal terbit tidak valid"),
+			errmsg.WithErrors("publishAt", "publishAt hanya dapat diisi saat status published."),
+		)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::UpdateProductStatus - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.GetContext(ctx, &current, tx.Rebind(`
+		SELECT status FROM products
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		FOR UPDATE
+	`), req.Id, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateProductStatus - Failed to get product")
+		return nil, err
+	}
+
+	before, err := getProductSnapshot(ctx, tx, req.Id, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !entity.CanTransitionProductStatus(current, req.Status) {
+		return nil, errmsg.NewCustomErrors(409,
+			errmsg.WithMessage("Status produk tidak dapat diubah"),
+			errmsg.WithErrors("status", fmt.Sprintf("status tidak dapat diubah dari %s ke %s.", current, req.Status)),
+		)
+	}
+
+	query := `
+		UPDATE products
+		SET status = ?, publish_at = ?, updated_at = NOW()
+		WHERE id = ?
+		RETURNING id, status, publish_at
+	`
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.Status, req.PublishAt, req.Id).StructScan(resp)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateProductStatus - Failed to update status")
+		return nil, err
+	}
+
+	if _, err := recordProductRevision(ctx, tx, req.Id, req.UserId, entity.RevisionActionStatus, before, ""); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateProductStatus - Failed to commit transaction")
+		return nil, err
+	}
+
+	resp.Status = entity.EffectiveProductStatus(resp.Status, resp.PublishAt)
+
+	return resp, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// GetTags returns the tags used by the most public products, optionally only
+// counting the products of one shop.
+func (r *shopRepository) GetTags(ctx context.Context, req *entity.GetTagsRequest) (*entity.GetTagsResponse, error) {
+	var (
+		resp = new(entity.GetTagsResponse)
+		args = make([]any, 0)
+	)
+	resp.Items = make([]entity.TagItem, 0, req.Limit)
+
+	query := `
+		SELECT t.name, COUNT(p.id) as product_count
+		FROM tags t
+		JOIN product_tags pt ON pt.tag_id = t.id
+		JOIN products p ON p.id = pt.product_id
+		WHERE p.deleted_at IS NULL AND ` + productVisible
+
+	if tags := entity.NormalizeTags([]string{req.Keyword}); len(tags) > 0 {
+		query += " AND t.name LIKE ?"
+		args = append(args, "%"+tags[0]+"%")
+	}
+	if req.ShopId != "" {
+		query += " AND p.shop_id = ?"
+		args = append(args, req.ShopId)
+	}
+
+	query += `
+		GROUP BY t.name
+		ORDER BY product_count DESC, t.name
+		LIMIT ?
+	`
+	args = append(args, req.Limit)
+
+	err := r.db.SelectContext(ctx, &resp.Items, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetTags - Failed to get tags")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// setProductTags replaces the tags of a product, creating the tags not used
+// before. It returns the normalized tags.
+func setProductTags(ctx context.Context, tx *sqlx.Tx, productId string, tags []string) ([]string, error) {
+	tags = entity.NormalizeTags(tags)
+
+	invalid := errmsg.NewCustomErrors(400, errmsg.WithMessage("Tag tidak valid"))
+	if len(tags) > entity.TagMaxCount {
+		invalid.Add("tags", fmt.Sprintf("tags harus tidak lebih dari %d.", entity.TagMaxCount))
+	}
+	for _, tag := range tags {
+		if len(tag) > entity.TagMaxLength {
+			invalid.Add("tags", fmt.Sprintf("tag %s harus tidak lebih dari %d karakter.", tag, entity.TagMaxLength))
+		}
+	}
+	if invalid.HasErrors() {
+		return nil, invalid
+	}
+
+	_, err := tx.ExecContext(ctx, tx.Rebind(`
+		DELETE FROM product_tags WHERE product_id = ?
+	`), productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::setProductTags - Failed to delete tags")
+		return nil, err
+	}
+
+	if len(tags) == 0 {
+		return tags, nil
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		INSERT INTO tags (name)
+		SELECT UNNEST(?::text[])
+		ON CONFLICT (name) DO NOTHING
+	`), pq.Array(tags))
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::setProductTags - Failed to create tags")
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		INSERT INTO product_tags (product_id, tag_id)
+		SELECT ?, id FROM tags WHERE name = ANY(?::text[])
+	`), productId, pq.Array(tags))
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::setProductTags - Failed to link tags")
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// getTagsByProductIds returns the tag names grouped by product id, sorted by
+// name.
+func (r *shopRepository) getTagsByProductIds(ctx context.Context, productIds []string) (map[string][]string, error) {
+	var (
+		result = make(map[string][]string)
+		rows   = make([]struct {
+			ProductId string `db:"product_id"`
+			Name      string `db:"name"`
+		}, 0)
+	)
+
+	if len(productIds) == 0 {
+		return result, nil
+	}
+
+	err := r.db.SelectContext(ctx, &rows, r.db.Rebind(`
+		SELECT pt.product_id, t.name
+		FROM product_tags pt
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.product_id = ANY(?::uuid[])
+		ORDER BY t.name
+	`), pq.Array(productIds))
+	if err != nil {
+		log.Error().Err(err).Msg("repository::getTagsByProductIds - Failed to get tags")
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.ProductId] = append(result[row.ProductId], row.Name)
+	}
+
+	return result, nil
+}
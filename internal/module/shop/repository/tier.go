@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// SetPriceTiers replaces the wholesale tiers of a product. The tiers are
+// expected sorted by quantity with decreasing prices, the first one has to be
+// cheaper than the product price.
+func (r *shopRepository) SetPriceTiers(ctx context.Context, req *entity.SetPriceTiersRequest) (*entity.SetPriceTiersResponse, error) {
+	var (
+		resp       = new(entity.SetPriceTiersResponse)
+		price      types.Money
+		quantities = make([]int64, len(req.Tiers))
+		prices     = make([]string, len(req.Tiers))
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::SetPriceTiers - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.GetContext(ctx, &price, tx.Rebind(`
+		SELECT price FROM products
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		FOR UPDATE
+	`), req.ProductId, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetPriceTiers - Failed to get product")
+		return nil, err
+	}
+
+	if len(req.Tiers) > 0 && req.Tiers[0].Price.Amount >= price.Amount {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Harga grosir tidak valid"),
+			errmsg.WithErrors("tiers", "harga grosir harus kurang dari harga produk."),
+		)
+	}
+
+	for i, t := range req.Tiers {
+		quantities[i] = int64(t.MinQuantity)
+		prices[i] = t.Price.String()
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		DELETE FROM product_price_tiers WHERE product_id = ?
+	`), req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetPriceTiers - Failed to delete tiers")
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(`
+		INSERT INTO product_price_tiers (product_id, min_quantity, price)
+		SELECT ?, t.min_quantity, t.price
+		FROM UNNEST(?::int[], ?::numeric[]) AS t(min_quantity, price)
+	`), req.ProductId, pq.Array(quantities), pq.Array(prices))
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetPriceTiers - Failed to insert tiers")
+		return nil, err
+	}
+
+	resp.Tiers, err = getPriceTiers(ctx, tx, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::SetPriceTiers - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetPricedProduct returns the prices, stock and tiers of a product a quote is
+// asked for.
+func (r *shopRepository) GetPricedProduct(ctx context.Context, req *entity.GetPriceQuoteRequest) (*entity.PricedProduct, error) {
+	var product = new(entity.PricedProduct)
+
+	err := r.db.GetContext(ctx, product, r.db.Rebind(`
+		SELECT
+			p.id,
+			p.price,
+			`+productPrice+` as effective_price,
+			COALESCE(`+bundleStock+`, (
+				SELECT SUM(v.stock) FROM product_variants v
+				WHERE v.product_id = p.id AND v.deleted_at IS NULL
+			), p.stock) as total_stock
+		FROM products p
+		WHERE p.id = ?
+			AND p.deleted_at IS NULL
+			AND (`+productVisible+` OR p.user_id::text = ?)
+	`), req.ProductId, req.UserId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetPricedProduct - Failed to get product")
+		return nil, err
+	}
+
+	product.Tiers, err = getPriceTiers(ctx, r.db, req.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// getPriceTiers returns the wholesale tiers of a product by quantity.
+func getPriceTiers(ctx context.Context, q sqlx.ExtContext, productId string) ([]entity.PriceTier, error) {
+	var tiers = make([]entity.PriceTier, 0)
+
+	err := sqlx.SelectContext(ctx, q, &tiers, q.Rebind(`
+		SELECT min_quantity, price
+		FROM product_price_tiers
+		WHERE product_id = ?
+		ORDER BY min_quantity
+	`), productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::getPriceTiers - Failed to get tiers")
+		return nil, err
+	}
+
+	return tiers, nil
+}
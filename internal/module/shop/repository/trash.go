@@ -0,0 +1,324 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// trashListQueries select the soft deleted rows of the owner, newest first.
+var trashListQueries = map[string]string{
+	entity.TrashShops: `
+		SELECT COUNT(id) OVER() as total_data, id, name, deleted_at
+		FROM shops
+		WHERE user_id = ? AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`,
+	entity.TrashProducts: `
+		SELECT COUNT(id) OVER() as total_data, id, name, shop_id, COALESCE(image_url, '') as image_url, deleted_at
+		FROM products
+		WHERE user_id = ? AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`,
+	entity.TrashCategories: `
+		SELECT COUNT(id) OVER() as total_data, id, name, parent_id, deleted_at
+		FROM categories
+		WHERE user_id = ? AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`,
+}
+
+func (r *shopRepository) GetTrash(ctx context.Context, req *entity.GetTrashRequest) (*entity.GetTrashResponse, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.TrashItem
+	}
+
+	var (
+		resp = new(entity.GetTrashResponse)
+		data = make([]dao, 0, req.Paginate)
+	)
+	resp.Items = make([]entity.TrashItem, 0, req.Paginate)
+
+	query := trashListQueries[req.Kind]
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), req.UserId, req.Paginate, req.Paginate*(req.Page-1))
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetTrash - Failed to get trash")
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		resp.Meta.TotalData = data[0].TotalData
+	}
+
+	for _, d := range data {
+		resp.Items = append(resp.Items, d.TrashItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *shopRepository) RestoreTrash(ctx context.Context, req *entity.RestoreTrashRequest) (*entity.RestoreTrashResponse, error) {
+	// parentCheck tells whether a row the restored one belongs to is deleted
+	type parentCheck struct {
+		query   string
+		message string
+	}
+
+	var (
+		resp = new(entity.RestoreTrashResponse)
+		// a row can only come back when the rows it belongs to are not deleted
+		parents []parentCheck
+		query   string
+	)
+
+	switch req.Kind {
+	case entity.TrashShops:
+		query = `
+			UPDATE shops
+			SET deleted_at = NULL, updated_at = NOW()
+			WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL
+			RETURNING id
+		`
+	case entity.TrashProducts:
+		parents = []parentCheck{
+			{
+				query: `
+					SELECT EXISTS (
+						SELECT 1 FROM products p
+						JOIN shops s ON s.id = p.shop_id
+						WHERE p.id = ? AND s.deleted_at IS NOT NULL
+					)
+				`,
+				message: "Toko dari produk ini masih berada di tempat sampah",
+			},
+			{
+				query: `
+					SELECT EXISTS (
+						SELECT 1 FROM products p
+						JOIN categories c ON c.id = p.category_id
+						WHERE p.id = ? AND c.deleted_at IS NOT NULL
+					)
+				`,
+				message: "Kategori dari produk ini masih berada di tempat sampah",
+			},
+		}
+		query = `
+			UPDATE products
+			SET deleted_at = NULL, updated_at = NOW()
+			WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL
+			RETURNING id
+		`
+	case entity.TrashCategories:
+		parents = []parentCheck{
+			{
+				query: `
+					SELECT EXISTS (
+						SELECT 1 FROM categories c
+						JOIN categories parent ON parent.id = c.parent_id
+						WHERE c.id = ? AND parent.deleted_at IS NOT NULL
+					)
+				`,
+				message: "Kategori induk masih berada di tempat sampah",
+			},
+		}
+		query = `
+			UPDATE categories
+			SET deleted_at = NULL, updated_at = NOW()
+			WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL
+			RETURNING id
+		`
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::RestoreTrash - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, parent := range parents {
+		var deleted bool
+		err := tx.GetContext(ctx, &deleted, tx.Rebind(parent.query), req.Id)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::RestoreTrash - Failed to check parent")
+			return nil, err
+		}
+		if deleted {
+			return nil, errmsg.NewCustomErrors(409, errmsg.WithMessage(parent.message))
+		}
+	}
+
+	var before string
+	if req.Kind == entity.TrashProducts {
+		before, err = getProductSnapshot(ctx, tx, req.Id, req.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query), req.Id, req.UserId).Scan(&resp.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Data tidak ditemukan di tempat sampah"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::RestoreTrash - Failed to restore")
+		return nil, err
+	}
+
+	if req.Kind == entity.TrashProducts {
+		if _, err := recordProductRevision(ctx, tx, req.Id, req.UserId, entity.RevisionActionRestore, before, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::RestoreTrash - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// PurgeTrash permanently removes the shops, products and categories deleted
+// before req.Before. Products of a purged shop go with it, variants, images and
+// reviews follow their product through ON DELETE CASCADE. A product still used
+// by a bundle that is kept stays, and so does its shop.
+func (r *shopRepository) PurgeTrash(ctx context.Context, req *entity.PurgeTrashRequest) (*entity.PurgeTrashResponse, error) {
+	var (
+		resp       = new(entity.PurgeTrashResponse)
+		productIds = make([]string, 0)
+		candidates []struct {
+			Id   string `db:"id"`
+			Kept bool   `db:"kept"`
+		}
+	)
+	resp.KeptComponentIds = make([]string, 0)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::PurgeTrash - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.SelectContext(ctx, &candidates, tx.Rebind(`
+		WITH purged AS (
+			SELECT p.id FROM products p
+			WHERE p.deleted_at < ?
+				OR p.shop_id IN (SELECT id FROM shops WHERE deleted_at < ?)
+		)
+		SELECT
+			purged.id,
+			EXISTS (
+				SELECT 1 FROM product_bundle_items bi
+				WHERE bi.product_id = purged.id
+					AND bi.bundle_id NOT IN (SELECT id FROM purged)
+			) as kept
+		FROM purged
+	`), req.Before, req.Before)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::PurgeTrash - Failed to get products")
+		return nil, err
+	}
+
+	for _, c := range candidates {
+		if c.Kept {
+			resp.KeptComponentIds = append(resp.KeptComponentIds, c.Id)
+		} else {
+			productIds = append(productIds, c.Id)
+		}
+	}
+
+	if len(productIds) > 0 {
+		// collect the images before the rows that point at them are gone
+		err = tx.SelectContext(ctx, &resp.ImageURLs, tx.Rebind(`
+			SELECT DISTINCT url FROM (
+				SELECT image_url as url FROM products WHERE id = ANY(?::uuid[])
+				UNION ALL
+				SELECT url FROM product_images WHERE product_id = ANY(?::uuid[])
+				UNION ALL
+				SELECT image_url FROM product_variants WHERE product_id = ANY(?::uuid[])
+				UNION ALL
+				SELECT UNNEST(photo_urls) FROM product_reviews WHERE product_id = ANY(?::uuid[])
+			) images
+			WHERE url IS NOT NULL AND url <> ''
+		`), pq.Array(productIds), pq.Array(productIds), pq.Array(productIds), pq.Array(productIds))
+		if err != nil {
+			log.Error().Err(err).Msg("repository::PurgeTrash - Failed to get images")
+			return nil, err
+		}
+
+		// the purged bundles let go of their components first, the components
+		// can't be deleted while a bundle points at them
+		_, err = tx.ExecContext(ctx, tx.Rebind(`
+			DELETE FROM product_bundle_items WHERE bundle_id = ANY(?::uuid[])
+		`), pq.Array(productIds))
+		if err != nil {
+			log.Error().Err(err).Msg("repository::PurgeTrash - Failed to delete bundle items")
+			return nil, err
+		}
+
+		result, err := tx.ExecContext(ctx, tx.Rebind(`
+			DELETE FROM products WHERE id = ANY(?::uuid[])
+		`), pq.Array(productIds))
+		if err != nil {
+			log.Error().Err(err).Msg("repository::PurgeTrash - Failed to delete products")
+			return nil, err
+		}
+
+		affected, _ := result.RowsAffected()
+		resp.Products = int(affected)
+	}
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(`
+		DELETE FROM shops s
+		WHERE s.deleted_at < ?
+			AND NOT EXISTS (SELECT 1 FROM products p WHERE p.shop_id = s.id)
+	`), req.Before)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::PurgeTrash - Failed to delete shops")
+		return nil, err
+	}
+
+	affected, _ := result.RowsAffected()
+	resp.Shops = int(affected)
+
+	// categories go leaf first, one still used by a product or by a category
+	// that is kept stays until the next run
+	for {
+		result, err := tx.ExecContext(ctx, tx.Rebind(`
+			DELETE FROM categories c
+			WHERE c.deleted_at < ?
+				AND NOT EXISTS (SELECT 1 FROM products p WHERE p.category_id = c.id)
+				AND NOT EXISTS (SELECT 1 FROM categories ch WHERE ch.parent_id = c.id)
+		`), req.Before)
+		if err != nil {
+			log.Error().Err(err).Msg("repository::PurgeTrash - Failed to delete categories")
+			return nil, err
+		}
+
+		affected, _ := result.RowsAffected()
+		if affected == 0 {
+			break
+		}
+		resp.Categories += int(affected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("repository::PurgeTrash - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
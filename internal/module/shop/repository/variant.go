@@ -0,0 +1,393 @@
+package repository
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+func (r *shopRepository) GetVariants(ctx context.Context, req *entity.GetVariantsRequest) (*entity.GetVariantsResponse, error) {
+	type optionDao struct {
+		Id     string         `db:"id"`
+		Name   string         `db:"name"`
+		Values pq.StringArray `db:"values"`
+	}
+
+	var (
+		resp    = new(entity.GetVariantsResponse)
+		options = make([]optionDao, 0)
+		exists  bool
+	)
+
+	err := r.db.GetContext(ctx, &exists, r.db.Rebind(`
+		SELECT EXISTS (
+			SELECT 1 FROM products p
+			WHERE p.id = ?
+				AND p.deleted_at IS NULL
+				AND (`+productVisible+` OR p.user_id::text = ?)
+		)
+	`), req.ProductId, req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetVariants - Failed to check product")
+		return nil, err
+	}
+	if !exists {
+		return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+	}
+
+	query := `
+		SELECT
+			o.id,
+			o.name,
+			ARRAY_AGG(DISTINCT vo.value ORDER BY vo.value) AS values
+		FROM product_options o
+		JOIN product_variant_options vo ON vo.option_id = o.id
+		JOIN product_variants v ON v.id = vo.variant_id AND v.deleted_at IS NULL
+		WHERE o.product_id = ?
+		GROUP BY o.id, o.name, o.position
+		ORDER BY o.position, o.name
+	`
+
+	err = r.db.SelectContext(ctx, &options, r.db.Rebind(query), req.ProductId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::GetVariants - Failed to get options")
+		return nil, err
+	}
+
+	resp.Options = make([]entity.OptionType, 0, len(options))
+	for _, o := range options {
+		resp.Options = append(resp.Options, entity.OptionType{
+			Id:     o.Id,
+			Name:   o.Name,
+			Values: o.Values,
+		})
+	}
+
+	variants, err := r.getVariantsByProductIds(ctx, []string{req.ProductId})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Variants = variants[req.ProductId]
+	if resp.Variants == nil {
+		resp.Variants = make([]entity.VariantItem, 0)
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) CreateVariant(ctx context.Context, req *entity.CreateVariantRequest) (*entity.CreateVariantResponse, error) {
+	var resp = new(entity.CreateVariantResponse)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::CreateVariant - Failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := r.checkProductOwner(ctx, tx, req.ProductId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := checkVariantOptionsFree(ctx, tx, req.ProductId, req.Options); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO product_variants (product_id, sku, price, stock, image_url)
+		VALUES (?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	err = tx.QueryRowxContext(ctx, tx.Rebind(query),
+		req.ProductId,
+		req.Sku,
+		req.Price,
+		req.Stock,
+		req.ImageURL,
+	).Scan(&resp.Id)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateVariant - Failed to create variant")
+		return nil, err
+	}
+
+	// insert option names in a stable order so positions are deterministic
+	names := make([]string, 0, len(req.Options))
+	for name := range req.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	optionQuery := `
+		INSERT INTO product_options (product_id, name, position)
+		VALUES (?, ?, (SELECT COALESCE(MAX(position) + 1, 0) FROM product_options WHERE product_id = ?))
+		ON CONFLICT (product_id, name) DO UPDATE SET updated_at = NOW()
+		RETURNING id
+	`
+
+	valueQuery := `
+		INSERT INTO product_variant_options (variant_id, option_id, value)
+		VALUES (?, ?, ?)
+	`
+
+	for _, name := range names {
+		var optionId string
+
+		err = tx.QueryRowxContext(ctx, tx.Rebind(optionQuery), req.ProductId, name, req.ProductId).Scan(&optionId)
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::CreateVariant - Failed to upsert option")
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, tx.Rebind(valueQuery), resp.Id, optionId, req.Options[name])
+		if err != nil {
+			log.Error().Err(err).Any("payload", req).Msg("repository::CreateVariant - Failed to insert option value")
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::CreateVariant - Failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) UpdateVariant(ctx context.Context, req *entity.UpdateVariantRequest) (*entity.UpdateVariantResponse, error) {
+	var resp = new(entity.UpdateVariantResponse)
+
+	query := `
+		UPDATE product_variants v
+		SET sku = ?, price = ?, stock = ?,
+			image_url = COALESCE(NULLIF(?, ''), v.image_url),
+			updated_at = NOW()
+		FROM products p
+		WHERE v.id = ?
+			AND v.product_id = ?
+			AND v.deleted_at IS NULL
+			AND p.id = v.product_id
+			AND p.user_id = ?
+			AND p.deleted_at IS NULL
+		RETURNING v.id
+	`
+
+	err := r.db.QueryRowxContext(ctx, r.db.Rebind(query),
+		req.Sku,
+		req.Price,
+		req.Stock,
+		req.ImageURL,
+		req.Id,
+		req.ProductId,
+		req.UserId).Scan(&resp.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Err(err).Any("payload", req).Msg("repository::UpdateVariant - Variant not found")
+			return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Varian tidak ditemukan"))
+		}
+		log.Error().Err(err).Any("payload", req).Msg("repository::UpdateVariant - Failed to update variant")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *shopRepository) DeleteVariant(ctx context.Context, req *entity.DeleteVariantRequest) error {
+	query := `
+		UPDATE product_variants v
+		SET deleted_at = NOW()
+		FROM products p
+		WHERE v.id = ?
+			AND v.product_id = ?
+			AND v.deleted_at IS NULL
+			AND p.id = v.product_id
+			AND p.user_id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id, req.ProductId, req.UserId)
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteVariant - Failed to delete variant")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("payload", req).Msg("repository::DeleteVariant - Failed to get affected rows")
+		return err
+	}
+	if affected == 0 {
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Varian tidak ditemukan"))
+	}
+
+	return nil
+}
+
+// checkProductOwner makes sure the product exists, is not deleted and belongs to the user.
+func (r *shopRepository) checkProductOwner(ctx context.Context, q sqlx.QueryerContext, productId, userId string) error {
+	var exists bool
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM products
+			WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+		)
+	`
+
+	err := sqlx.GetContext(ctx, q, &exists, r.db.Rebind(query), productId, userId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::checkProductOwner - Failed to check product")
+		return err
+	}
+	if !exists {
+		return errmsg.NewCustomErrors(404, errmsg.WithMessage("Produk tidak ditemukan"))
+	}
+
+	return nil
+}
+
+// checkVariantOptionsFree refuses an option combination an active variant of
+// the product already has. The product is locked so two requests can't add
+// the same combination at once.
+func checkVariantOptionsFree(ctx context.Context, tx *sqlx.Tx, productId string, options map[string]string) error {
+	var (
+		names  = make([]string, 0, len(options))
+		values = make([]string, 0, len(options))
+		taken  bool
+	)
+
+	for name, value := range options {
+		names = append(names, name)
+		values = append(values, value)
+	}
+
+	_, err := tx.ExecContext(ctx, tx.Rebind(`
+		SELECT 1 FROM products WHERE id = ? FOR UPDATE
+	`), productId)
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::checkVariantOptionsFree - Failed to lock product")
+		return err
+	}
+
+	err = tx.GetContext(ctx, &taken, tx.Rebind(`
+		SELECT EXISTS (
+			SELECT 1 FROM product_variants v
+			WHERE v.product_id = ?
+				AND v.deleted_at IS NULL
+				AND (SELECT COUNT(*) FROM product_variant_options vo WHERE vo.variant_id = v.id) = ?
+				AND NOT EXISTS (
+					SELECT 1 FROM UNNEST(?::text[], ?::text[]) AS f(name, value)
+					WHERE NOT EXISTS (
+						SELECT 1 FROM product_variant_options vo
+						JOIN product_options o ON o.id = vo.option_id
+						WHERE vo.variant_id = v.id AND o.name = f.name AND vo.value = f.value
+					)
+				)
+		)
+	`), productId, len(options), pq.Array(names), pq.Array(values))
+	if err != nil {
+		log.Error().Err(err).Str("product_id", productId).Msg("repository::checkVariantOptionsFree - Failed to check options")
+		return err
+	}
+	if taken {
+		return errmsg.NewCustomErrors(409,
+			errmsg.WithMessage("Varian sudah ada"),
+			errmsg.WithErrors("options", "kombinasi opsi sudah digunakan varian lain."),
+		)
+	}
+
+	return nil
+}
+
+// getVariantsByProductIds returns the active variants grouped by product id.
+func (r *shopRepository) getVariantsByProductIds(ctx context.Context, productIds []string) (map[string][]entity.VariantItem, error) {
+	type optionDao struct {
+		VariantId string `db:"variant_id"`
+		entity.VariantOption
+	}
+
+	var (
+		result   = make(map[string][]entity.VariantItem)
+		variants = make([]entity.VariantItem, 0)
+		options  = make([]optionDao, 0)
+	)
+
+	if len(productIds) == 0 {
+		return result, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT
+			id,
+			product_id,
+			sku,
+			price,
+			stock,
+			COALESCE(image_url, '') AS image_url
+		FROM product_variants
+		WHERE product_id IN (?)
+			AND deleted_at IS NULL
+		ORDER BY created_at, sku
+	`, productIds)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::getVariantsByProductIds - Failed to construct variant query")
+		return nil, err
+	}
+
+	err = r.db.SelectContext(ctx, &variants, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::getVariantsByProductIds - Failed to get variants")
+		return nil, err
+	}
+
+	if len(variants) == 0 {
+		return result, nil
+	}
+
+	variantIds := make([]string, len(variants))
+	for i, v := range variants {
+		variantIds[i] = v.Id
+	}
+
+	query, args, err = sqlx.In(`
+		SELECT
+			vo.variant_id,
+			o.name,
+			vo.value
+		FROM product_variant_options vo
+		JOIN product_options o ON o.id = vo.option_id
+		WHERE vo.variant_id IN (?)
+		ORDER BY o.position, o.name
+	`, variantIds)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::getVariantsByProductIds - Failed to construct option query")
+		return nil, err
+	}
+
+	err = r.db.SelectContext(ctx, &options, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Msg("repository::getVariantsByProductIds - Failed to get variant options")
+		return nil, err
+	}
+
+	optionMap := make(map[string][]entity.VariantOption)
+	for _, o := range options {
+		optionMap[o.VariantId] = append(optionMap[o.VariantId], o.VariantOption)
+	}
+
+	for _, v := range variants {
+		v.Options = optionMap[v.Id]
+		if v.Options == nil {
+			v.Options = make([]entity.VariantOption, 0)
+		}
+		result[v.ProductId] = append(result[v.ProductId], v)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"cmp"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"strings"
+)
+
+// attributeCodeMaxLength matches the code column of category_attributes.
+const attributeCodeMaxLength = 50
+
+func (s *shopService) GetCategoryAttributes(ctx context.Context, req *entity.GetCategoryAttributesRequest) (*entity.GetCategoryAttributesResponse, error) {
+	return s.repo.GetCategoryAttributes(ctx, req)
+}
+
+// CreateCategoryAttribute normalizes the code to a slug so it can be used as
+// is in an attr[...] query parameter.
+func (s *shopService) CreateCategoryAttribute(ctx context.Context, req *entity.CreateCategoryAttributeRequest) (*entity.CreateCategoryAttributeResponse, error) {
+	code := pkg.Slugify(cmp.Or(req.Code, req.Name))
+	if len(code) > attributeCodeMaxLength {
+		code = strings.TrimSuffix(code[:attributeCodeMaxLength], "-")
+	}
+	if code == "" {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Atribut tidak valid"),
+			errmsg.WithErrors("code", "harus berisi huruf atau angka."),
+		)
+	}
+	req.Code = code
+
+	return s.repo.CreateCategoryAttribute(ctx, req)
+}
+
+func (s *shopService) UpdateCategoryAttribute(ctx context.Context, req *entity.UpdateCategoryAttributeRequest) (*entity.UpdateCategoryAttributeResponse, error) {
+	return s.repo.UpdateCategoryAttribute(ctx, req)
+}
+
+func (s *shopService) DeleteCategoryAttribute(ctx context.Context, req *entity.DeleteCategoryAttributeRequest) error {
+	return s.repo.DeleteCategoryAttribute(ctx, req)
+}
+
+// SetProductAttributes checks every value against the attribute definitions
+// of the product's category and stores them in their normalized form. An
+// empty value removes the attribute from the product.
+func (s *shopService) SetProductAttributes(ctx context.Context, req *entity.SetProductAttributesRequest) (*entity.SetProductAttributesResponse, error) {
+	defs, err := s.repo.GetProductAttributeDefinitions(ctx, req.ProductId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		byCode = make(map[string]entity.CategoryAttribute, len(defs))
+		values = make([]entity.ProductAttribute, 0, len(req.Attributes))
+		errs   = make([]errmsg.Option, 0)
+	)
+
+	for _, def := range defs {
+		byCode[def.Code] = def
+	}
+
+	for code, raw := range req.Attributes {
+		def, ok := byCode[code]
+		if !ok {
+			errs = append(errs, errmsg.WithErrors("attributes."+code, "atribut tidak tersedia untuk kategori produk ini."))
+			continue
+		}
+
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		value, msg := normalizeAttributeValue(def, raw)
+		if msg != "" {
+			errs = append(errs, errmsg.WithErrors("attributes."+code, msg))
+			continue
+		}
+
+		values = append(values, entity.ProductAttribute{AttributeId: def.Id, Code: code, Value: value})
+	}
+
+	if len(errs) > 0 {
+		return nil, errmsg.NewCustomErrors(400, append([]errmsg.Option{errmsg.WithMessage("Atribut produk tidak valid")}, errs...)...)
+	}
+
+	return s.repo.SetProductAttributes(ctx, req, values)
+}
+
+// normalizeAttributeValue returns the stored form of raw for the attribute,
+// or a validation message when raw does not fit its type.
+func normalizeAttributeValue(def entity.CategoryAttribute, raw string) (string, string) {
+	switch def.Type {
+	case entity.AttributeTypeNumber:
+		value, ok := entity.NormalizeAttributeNumber(raw)
+		if !ok {
+			return "", "harus berupa angka."
+		}
+		return value, ""
+	case entity.AttributeTypeBoolean:
+		value, ok := entity.NormalizeAttributeBoolean(raw)
+		if !ok {
+			return "", "harus berupa true atau false."
+		}
+		return value, ""
+	case entity.AttributeTypeEnum:
+		for _, allowed := range def.AllowedValues {
+			if strings.EqualFold(allowed, raw) {
+				return allowed, ""
+			}
+		}
+		return "", "harus salah satu dari: " + strings.Join(def.AllowedValues, ", ") + "."
+	}
+
+	return raw, ""
+}
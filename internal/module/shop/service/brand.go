@@ -0,0 +1,130 @@
+package service
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"sort"
+	"strings"
+)
+
+func (s *shopService) GetBrands(ctx context.Context, req *entity.GetBrandsRequest) (*entity.GetBrandsResponse, error) {
+	return s.repo.GetBrands(ctx, req)
+}
+
+func (s *shopService) GetBrand(ctx context.Context, req *entity.GetBrandRequest) (*entity.BrandDetail, error) {
+	return s.repo.GetBrand(ctx, req)
+}
+
+// UpdateBrand collapses the spaces of the name, which needs a letter or a
+// digit to have an alias.
+func (s *shopService) UpdateBrand(ctx context.Context, req *entity.UpdateBrandRequest) (*entity.BrandDetail, error) {
+	req.Name = strings.Join(strings.Fields(req.Name), " ")
+	if entity.BrandKey(req.Name) == "" {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Merek tidak valid"),
+			errmsg.WithErrors("name", "harus berisi huruf atau angka."),
+		)
+	}
+
+	return s.repo.UpdateBrand(ctx, req)
+}
+
+// AddBrandAlias stores the alias in its BrandKey form, the one merks are
+// looked up by.
+func (s *shopService) AddBrandAlias(ctx context.Context, req *entity.AddBrandAliasRequest) (*entity.BrandDetail, error) {
+	req.Alias = entity.BrandKey(req.Alias)
+	if req.Alias == "" {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Alias tidak valid"),
+			errmsg.WithErrors("alias", "harus berisi huruf atau angka."),
+		)
+	}
+
+	return s.repo.AddBrandAlias(ctx, req)
+}
+
+// DeleteBrandAlias accepts any spelling of the alias.
+func (s *shopService) DeleteBrandAlias(ctx context.Context, req *entity.DeleteBrandAliasRequest) (*entity.BrandDetail, error) {
+	req.Alias = entity.BrandKey(req.Alias)
+	if req.Alias == "" {
+		return nil, errmsg.NewCustomErrors(404, errmsg.WithMessage("Alias tidak ditemukan"))
+	}
+
+	return s.repo.DeleteBrandAlias(ctx, req)
+}
+
+// NormalizeBrands groups the merk values of the unlinked products by their
+// brand key and links every group to one brand.
+func (s *shopService) NormalizeBrands(ctx context.Context, req *entity.NormalizeBrandsRequest) (*entity.NormalizeBrandsResponse, error) {
+	var resp = new(entity.NormalizeBrandsResponse)
+
+	merks, err := s.repo.GetUnbrandedMerks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Groups = groupMerks(merks)
+
+	if req.DryRun {
+		for _, g := range resp.Groups {
+			resp.Products += g.Products
+		}
+		return resp, nil
+	}
+
+	resp.Products, err = s.repo.LinkBrands(ctx, resp.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// groupMerks puts the spellings sharing a brand key together. The group is
+// named after the cleaned spelling used by the most products, ties go to the
+// one sorting first. Merks without a key are left alone.
+func groupMerks(merks []entity.MerkCount) []entity.BrandGroup {
+	var (
+		groups = make(map[string]*entity.BrandGroup)
+		names  = make(map[string]map[string]int)
+		keys   = make([]string, 0)
+	)
+
+	for _, m := range merks {
+		key := entity.BrandKey(m.Merk)
+		if key == "" {
+			continue
+		}
+
+		g, ok := groups[key]
+		if !ok {
+			g = &entity.BrandGroup{Key: key}
+			groups[key] = g
+			names[key] = make(map[string]int)
+			keys = append(keys, key)
+		}
+
+		g.Merks = append(g.Merks, m.Merk)
+		g.Products += m.Products
+		names[key][entity.BrandName(m.Merk)] += m.Products
+	}
+
+	sort.Strings(keys)
+
+	result := make([]entity.BrandGroup, 0, len(keys))
+	for _, key := range keys {
+		g := groups[key]
+
+		for name, count := range names[key] {
+			best := names[key][g.Name]
+			if g.Name == "" || count > best || (count == best && name < g.Name) {
+				g.Name = name
+			}
+		}
+
+		result = append(result, *g)
+	}
+
+	return result
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"cmp"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"context"
+	"fmt"
+)
+
+// SetExchangeRates stores rates against the default currency, whose own rate
+// is always 1.
+func (s *shopService) SetExchangeRates(ctx context.Context, req *entity.SetExchangeRatesRequest) (*entity.GetExchangeRatesResponse, error) {
+	for _, rate := range req.Rates {
+		if rate.Currency == types.DefaultCurrency {
+			return nil, errmsg.NewCustomErrors(400,
+				errmsg.WithMessage("Kurs tidak valid"),
+				errmsg.WithErrors("rates", fmt.Sprintf("kurs %s selalu 1.", types.DefaultCurrency)),
+			)
+		}
+	}
+
+	if err := s.repo.SetExchangeRates(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return s.GetExchangeRates(ctx)
+}
+
+func (s *shopService) GetExchangeRates(ctx context.Context) (*entity.GetExchangeRatesResponse, error) {
+	rates, err := s.repo.GetExchangeRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.GetExchangeRatesResponse{Base: types.DefaultCurrency, Rates: rates}, nil
+}
+
+// priceConverter converts product prices to the currency a buyer asked for.
+// A nil converter leaves the products untouched.
+type priceConverter struct {
+	currency string
+	rates    map[string]entity.ExchangeRate
+}
+
+// newPriceConverter returns nil when no currency is asked for and refuses a
+// currency without a rate.
+func (s *shopService) newPriceConverter(ctx context.Context, currency string) (*priceConverter, error) {
+	if currency == "" {
+		return nil, nil
+	}
+
+	rates, err := s.repo.GetExchangeRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &priceConverter{
+		currency: currency,
+		rates: map[string]entity.ExchangeRate{
+			types.DefaultCurrency: {Currency: types.DefaultCurrency, Rate: 1},
+		},
+	}
+	for _, rate := range rates {
+		c.rates[rate.Currency] = rate
+	}
+
+	if _, ok := c.rates[currency]; !ok {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("Mata uang tidak didukung"),
+			errmsg.WithErrors("currency", "kurs mata uang ini belum tersedia."),
+		)
+	}
+
+	return c, nil
+}
+
+// convert returns the prices in the asked currency. It is nil when they are in
+// that currency already or the rate of the shop currency is unknown.
+func (c *priceConverter) convert(from string, price, effectivePrice, minPrice, maxPrice types.Money) *entity.ConvertedPrice {
+	from = cmp.Or(from, types.DefaultCurrency)
+	if c == nil || from == c.currency {
+		return nil
+	}
+
+	source, ok := c.rates[from]
+	if !ok {
+		return nil
+	}
+
+	var (
+		target    = c.rates[c.currency]
+		rate      = source.Rate / target.Rate
+		updatedAt = source.UpdatedAt
+	)
+
+	// the default currency has no row, the other side tells how fresh it is
+	if target.UpdatedAt.After(updatedAt) {
+		updatedAt = target.UpdatedAt
+	}
+
+	return &entity.ConvertedPrice{
+		Currency:       c.currency,
+		Rate:           rate,
+		Indicative:     true,
+		RateUpdatedAt:  updatedAt,
+		Price:          price.Convert(rate, c.currency),
+		EffectivePrice: effectivePrice.Convert(rate, c.currency),
+		MinPrice:       minPrice.Convert(rate, c.currency),
+		MaxPrice:       maxPrice.Convert(rate, c.currency),
+	}
+}
+
+func (c *priceConverter) product(p *entity.ProductItem) {
+	p.Converted = c.convert(p.Currency, p.Price, p.EffectivePrice, p.MinPrice, p.MaxPrice)
+}
+
+func (c *priceConverter) productDetail(p *entity.GetProductIdResponse) {
+	p.Converted = c.convert(p.Currency, p.Price, p.EffectivePrice, p.MinPrice, p.MaxPrice)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/types"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceConverter(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	c := &priceConverter{
+		currency: "USD",
+		rates: map[string]entity.ExchangeRate{
+			types.DefaultCurrency: {Currency: types.DefaultCurrency, Rate: 1},
+			"USD":                 {Currency: "USD", Rate: 16000, UpdatedAt: updatedAt},
+			"SGD":                 {Currency: "SGD", Rate: 12000, UpdatedAt: updatedAt.Add(-time.Hour)},
+		},
+	}
+
+	price := types.NewMoney(4000000, types.DefaultCurrency)
+	converted := c.convert("", price, price, price, price)
+	if assert.NotNil(t, converted) {
+		assert.True(t, converted.Indicative)
+		assert.Equal(t, "USD", converted.Currency)
+		assert.Equal(t, int64(250), converted.Price.Amount)
+		assert.Equal(t, updatedAt, converted.RateUpdatedAt)
+	}
+
+	// cross rate through the default currency
+	sgd := types.NewMoney(2000, "SGD")
+	converted = c.convert("SGD", sgd, sgd, sgd, sgd)
+	if assert.NotNil(t, converted) {
+		assert.Equal(t, int64(1500), converted.EffectivePrice.Amount)
+		assert.Equal(t, updatedAt, converted.RateUpdatedAt)
+	}
+
+	assert.Nil(t, c.convert("USD", price, price, price, price))
+	assert.Nil(t, c.convert("EUR", price, price, price, price))
+	assert.Nil(t, (*priceConverter)(nil).convert("USD", price, price, price, price))
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportFlushEvery is the number of rows buffered before they are pushed to
+// the client.
+const exportFlushEvery = 100
+
+// exportCSVHeader uses the import column names, so an export can serve as an
+// import template. Import ignores id and the computed columns and always
+// creates new products, importing an export back duplicates them.
+var exportCSVHeader = []string{
+	"id", "name", "description", "merk", "categoryId", "category", "price", "minPrice",
+	"maxPrice", "stock", "totalStock", "rating", "reviewCount", "imageUrl", "imageUrls",
+	"status", "publishAt",
+}
+
+func (s *shopService) ExportProducts(ctx context.Context, req *entity.ExportProductsRequest) (entity.ExportWriter, error) {
+	// ownership is checked up front, errors can't be reported once streaming
+	if err := s.repo.CheckShopOwner(ctx, req.ShopId, req.UserId); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, w io.Writer) error {
+		if req.Format == entity.ExportFormatNDJSON {
+			return s.exportNDJSON(ctx, req, w)
+		}
+
+		return s.exportCSV(ctx, req, w)
+	}, nil
+}
+
+func (s *shopService) exportCSV(ctx context.Context, req *entity.ExportProductsRequest, w io.Writer) error {
+	var (
+		cw    = csv.NewWriter(w)
+		count int
+	)
+
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return err
+	}
+
+	err := s.repo.ExportProducts(ctx, req, func(item *entity.ExportProductItem) error {
+		err := cw.Write([]string{
+			item.Id,
+			item.Name,
+			item.Description,
+			item.Merk,
+			item.CategoryId,
+			item.Category,
+			item.Price.String(),
+			item.MinPrice.String(),
+			item.MaxPrice.String(),
+			strconv.Itoa(item.Stock),
+			strconv.Itoa(item.TotalStock),
+			formatExportFloat(item.Rating),
+			strconv.Itoa(item.ReviewCount),
+			item.ImageURL,
+			strings.Join(item.ImageURLs, "|"),
+			item.Status,
+			formatExportTime(item.PublishAt),
+		})
+		if err != nil {
+			return err
+		}
+
+		count++
+		if count%exportFlushEvery == 0 {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+			return flushExport(w)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+
+	return flushExport(w)
+}
+
+func (s *shopService) exportNDJSON(ctx context.Context, req *entity.ExportProductsRequest, w io.Writer) error {
+	var (
+		enc   = json.NewEncoder(w)
+		count int
+	)
+
+	err := s.repo.ExportProducts(ctx, req, func(item *entity.ExportProductItem) error {
+		if item.ImageURLs == nil {
+			item.ImageURLs = make([]string, 0)
+		}
+
+		// Encode terminates every value with a newline
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+
+		count++
+		if count%exportFlushEvery == 0 {
+			return flushExport(w)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flushExport(w)
+}
+
+// flushExport pushes buffered rows to the client when w supports it.
+func flushExport(w io.Writer) error {
+	if f, ok := w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+func formatExportFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// importMaxRows caps the number of products in a single import file.
+const importMaxRows = 1000
+
+// importColumns maps the normalized header names to the json field they fill,
+// so the report uses the same field names as POST /products.
+var importColumns = map[string]string{
+	"name":        "name",
+	"description": "description",
+	"price":       "price",
+	"stock":       "stock",
+	"merk":        "merk",
+	"categoryid":  "categoryId",
+	"imageurl":    "imageUrl",
+	"status":      "status",
+}
+
+var importRequiredColumns = []string{"name", "description", "price", "stock", "merk", "categoryId"}
+
+func (s *shopService) ImportProducts(ctx context.Context, req *entity.ImportProductsRequest) (*entity.ImportProductsResponse, error) {
+	var (
+		resp  = new(entity.ImportProductsResponse)
+		valid = make([]entity.ImportProductRow, 0)
+		v     = adapter.Adapters.Validator
+	)
+	resp.Rows = make([]entity.ImportRowResult, 0)
+
+	// the header comes on top of the products
+	records, err := pkg.ReadSpreadsheet(req.FileName, req.File, importMaxRows+1)
+	if errors.Is(err, pkg.ErrTooManyRows) {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("File terlalu besar"),
+			errmsg.WithErrors("file", fmt.Sprintf("file harus tidak lebih dari %d baris produk.", importMaxRows)),
+		)
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("file", req.FileName).Msg("service::ImportProducts - Failed to read file")
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("File tidak dapat dibaca"),
+			errmsg.WithErrors("file", "file harus berupa CSV atau XLSX yang valid."),
+		)
+	}
+
+	if len(records) == 0 {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("File kosong"),
+			errmsg.WithErrors("file", "file harus memiliki baris header."),
+		)
+	}
+
+	columns, err := importHeader(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for i, record := range records[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+
+		// the header is line 1
+		row := entity.ImportProductRow{Row: i + 2}
+		row.Product.UserId = req.UserId
+		row.Product.ShopId = req.ShopId
+
+		errs := make(map[string][]string)
+		for idx, field := range columns {
+			if idx >= len(record) {
+				continue
+			}
+			setImportField(&row.Product, field, strings.TrimSpace(record[idx]), errs)
+		}
+
+		if err := v.Validate(&row.Product); err != nil {
+			for field, msgs := range errmsg.FieldErrors(err, &row.Product) {
+				// a number that failed to parse is already reported
+				if _, ok := errs[field]; !ok {
+					errs[field] = msgs
+				}
+			}
+		}
+
+		if len(errs) > 0 {
+			resp.Rows = append(resp.Rows, entity.ImportRowResult{
+				Row:    row.Row,
+				Status: entity.ImportStatusFailed,
+				Errors: errs,
+			})
+			continue
+		}
+
+		valid = append(valid, row)
+	}
+
+	resp.TotalRows = len(resp.Rows) + len(valid)
+	if resp.TotalRows > importMaxRows {
+		return nil, errmsg.NewCustomErrors(400,
+			errmsg.WithMessage("File terlalu besar"),
+			errmsg.WithErrors("file", fmt.Sprintf("file harus tidak lebih dari %d baris produk.", importMaxRows)),
+		)
+	}
+
+	results, err := s.repo.ImportProducts(ctx, req, valid)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Rows = append(resp.Rows, results...)
+	sort.Slice(resp.Rows, func(i, j int) bool {
+		return resp.Rows[i].Row < resp.Rows[j].Row
+	})
+
+	for _, row := range resp.Rows {
+		if row.Status == entity.ImportStatusImported {
+			resp.Imported++
+		} else {
+			resp.Failed++
+		}
+	}
+
+	return resp, nil
+}
+
+// importHeader returns the field filled by each column of the file, unknown
+// columns are ignored.
+func importHeader(header []string) (map[int]string, error) {
+	var (
+		columns = make(map[int]string)
+		found   = make(map[string]bool)
+	)
+
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		key = strings.NewReplacer("_", "", " ", "", "-", "").Replace(key)
+
+		if field, ok := importColumns[key]; ok {
+			columns[i] = field
+			found[field] = true
+		}
+	}
+
+	missing := errmsg.NewCustomErrors(400, errmsg.WithMessage("Header file tidak lengkap"))
+	for _, field := range importRequiredColumns {
+		if !found[field] {
+			missing.Add("file", fmt.Sprintf("kolom %s harus ada.", field))
+		}
+	}
+
+	if missing.HasErrors() {
+		return nil, missing
+	}
+
+	return columns, nil
+}
+
+func setImportField(p *entity.CreateProductRequest, field, value string, errs map[string][]string) {
+	switch field {
+	case "name":
+		p.Name = value
+	case "description":
+		p.Description = value
+	case "merk":
+		p.Merk = value
+	case "categoryId":
+		p.CategoryId = value
+	case "imageUrl":
+		p.ImageURL = value
+	case "status":
+		p.Status = value
+	case "price":
+		if value == "" {
+			return
+		}
+
+		price, err := types.ParseMoney(value)
+		if err != nil {
+			errs[field] = append(errs[field], fmt.Sprintf("%s harus berupa angka.", field))
+			return
+		}
+
+		p.Price = price
+	case "stock":
+		if value == "" {
+			return
+		}
+
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			errs[field] = append(errs[field], fmt.Sprintf("%s harus berupa angka.", field))
+			return
+		}
+
+		p.Stock = n
+	}
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/types"
+	"context"
+	"math"
+	"sort"
+)
+
+// The weights of the related products score, they add up to 1.
+const (
+	relatedCategoryWeight = 0.4
+	relatedBrandWeight    = 0.25
+	relatedPriceWeight    = 0.15
+	relatedTextWeight     = 0.2
+)
+
+func (s *shopService) GetRelatedProducts(ctx context.Context, req *entity.GetRelatedProductsRequest) (*entity.GetRelatedProductsResponse, error) {
+	var resp = new(entity.GetRelatedProductsResponse)
+
+	target, err := s.repo.GetRelatedTarget(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.repo.GetRelatedCandidates(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Items = rankRelated(target, candidates, req.Limit)
+
+	return resp, nil
+}
+
+// rankRelated scores the candidates against the target and returns the best
+// ones first. Equal scores go to the better rated, then the more reviewed
+// product, the id settles the rest so the order never changes between calls.
+func rankRelated(target *entity.RelatedCandidate, candidates []entity.RelatedCandidate, limit int) []entity.RelatedProduct {
+	var (
+		targetTokens = entity.NameTokens(target.Name)
+		items        = make([]entity.RelatedProduct, 0, len(candidates))
+	)
+
+	for _, c := range candidates {
+		if c.Id == target.Id {
+			continue
+		}
+
+		score := relatedScore(target, &c, targetTokens)
+		if score <= 0 {
+			continue
+		}
+
+		items = append(items, entity.RelatedProduct{
+			Id:          c.Id,
+			Slug:        c.Slug,
+			Name:        c.Name,
+			ShopId:      c.ShopId,
+			Merk:        c.Merk,
+			Price:       c.Price,
+			Rating:      c.Rating,
+			ReviewCount: c.ReviewCount,
+			ImageURL:    c.ImageURL,
+			Score:       score,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		switch {
+		case a.Score != b.Score:
+			return a.Score > b.Score
+		case a.Rating != b.Rating:
+			return a.Rating > b.Rating
+		case a.ReviewCount != b.ReviewCount:
+			return a.ReviewCount > b.ReviewCount
+		default:
+			return a.Id < b.Id
+		}
+	})
+
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// relatedScore weighs how close a candidate is to the target, from 0 to 1,
+// rounded to 4 decimals so float noise does not change the order.
+func relatedScore(target, c *entity.RelatedCandidate, targetTokens []string) float64 {
+	score := relatedCategoryWeight*categoryScore(target, c) +
+		relatedBrandWeight*brandScore(target, c) +
+		relatedPriceWeight*basePriceScore(target.BasePrice, c.BasePrice) +
+		relatedTextWeight*textScore(targetTokens, entity.NameTokens(c.Name))
+
+	return math.Round(score*10000) / 10000
+}
+
+// categoryScore is 1 for the same category and 0.5 for a parent, child or
+// sibling category.
+func categoryScore(target, c *entity.RelatedCandidate) float64 {
+	switch {
+	case c.CategoryId == target.CategoryId:
+		return 1
+	case c.ParentCategoryId != nil && *c.ParentCategoryId == target.CategoryId,
+		target.ParentCategoryId != nil && *target.ParentCategoryId == c.CategoryId,
+		c.ParentCategoryId != nil && target.ParentCategoryId != nil && *c.ParentCategoryId == *target.ParentCategoryId:
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// brandScore is 1 for the same brand. Products not linked to a brand yet are
+// compared by their merk.
+func brandScore(target, c *entity.RelatedCandidate) float64 {
+	if target.BrandId != nil && c.BrandId != nil {
+		if *target.BrandId == *c.BrandId {
+			return 1
+		}
+		return 0
+	}
+
+	if key := entity.BrandKey(target.Merk); key != "" && key == entity.BrandKey(c.Merk) {
+		return 1
+	}
+
+	return 0
+}
+
+// basePriceScore compares prices in the default currency, a price whose rate is
+// unknown is not close to any other.
+func basePriceScore(a, b *types.Money) float64 {
+	if a == nil || b == nil {
+		return 0
+	}
+
+	return priceScore(a.Float64(), b.Float64())
+}
+
+// priceScore is 1 for the same price and goes down to 0 as one price gets
+// further from the other, relative to the higher one.
+func priceScore(a, b float64) float64 {
+	high := math.Max(a, b)
+	if high <= 0 {
+		return 1
+	}
+
+	return 1 - math.Abs(a-b)/high
+}
+
+// textScore is the share of name words the two products have in common.
+func textScore(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	words := make(map[string]bool, len(a))
+	for _, w := range a {
+		words[w] = true
+	}
+
+	shared := 0
+	for _, w := range b {
+		if words[w] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
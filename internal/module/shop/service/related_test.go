@@ -0,0 +1,92 @@
+package service
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func idrPtr(amount int64) *types.Money {
+	m := types.NewMoney(amount, types.DefaultCurrency)
+	return &m
+}
+
+func relatedIds(items []entity.RelatedProduct) []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.Id)
+	}
+	return ids
+}
+
+func TestRankRelated(t *testing.T) {
+	target := &entity.RelatedCandidate{
+		Id:               "target",
+		Name:             "Kaos Polos Cotton Hitam",
+		CategoryId:       "kaos",
+		ParentCategoryId: strPtr("pakaian"),
+		BrandId:          strPtr("erigo"),
+		Merk:             "Erigo",
+		Price:            types.NewMoney(10000, types.DefaultCurrency), BasePrice: idrPtr(10000),
+	}
+
+	candidates := []entity.RelatedCandidate{
+		{Id: "topi-a", Name: "Topi", CategoryId: "topi", BrandId: strPtr("erigo"), Price: types.NewMoney(10000, types.DefaultCurrency), BasePrice: idrPtr(10000), Rating: 4},
+		{Id: "kemeja", Name: "Kemeja Hitam", CategoryId: "kemeja", ParentCategoryId: strPtr("pakaian"), Merk: "Lain", Price: types.NewMoney(5000, types.DefaultCurrency), BasePrice: idrPtr(5000)},
+		{Id: "target", Name: "Kaos Polos Cotton Hitam", CategoryId: "kaos", Price: types.NewMoney(10000, types.DefaultCurrency), BasePrice: idrPtr(10000)},
+		{Id: "kaos", Name: "Kaos Polos Cotton Putih", CategoryId: "kaos", ParentCategoryId: strPtr("pakaian"), BrandId: strPtr("erigo"), Price: types.NewMoney(10000, types.DefaultCurrency), BasePrice: idrPtr(10000)},
+		{Id: "topi-c", Name: "Topi", CategoryId: "topi", BrandId: strPtr("erigo"), Price: types.NewMoney(10000, types.DefaultCurrency), BasePrice: idrPtr(10000), Rating: 4.5},
+		{Id: "topi-b", Name: "Topi", CategoryId: "topi", BrandId: strPtr("erigo"), Price: types.NewMoney(10000, types.DefaultCurrency), BasePrice: idrPtr(10000), Rating: 4.5},
+	}
+
+	items := rankRelated(target, candidates, 10)
+
+	assert.Equal(t, []string{"kaos", "topi-b", "topi-c", "topi-a", "kemeja"}, relatedIds(items))
+	assert.Equal(t, 0.92, items[0].Score)
+	assert.Equal(t, 0.4, items[1].Score)
+	assert.Equal(t, 0.315, items[4].Score)
+
+	// the same input always gives the same order
+	assert.Equal(t, items, rankRelated(target, candidates, 10))
+
+	assert.Equal(t, []string{"kaos", "topi-b"}, relatedIds(rankRelated(target, candidates, 2)))
+}
+
+func TestCategoryScore(t *testing.T) {
+	target := &entity.RelatedCandidate{CategoryId: "kaos", ParentCategoryId: strPtr("pakaian")}
+
+	assert.Equal(t, 1.0, categoryScore(target, &entity.RelatedCandidate{CategoryId: "kaos"}))
+	assert.Equal(t, 0.5, categoryScore(target, &entity.RelatedCandidate{CategoryId: "pakaian"}))
+	assert.Equal(t, 0.5, categoryScore(target, &entity.RelatedCandidate{CategoryId: "kaos-polo", ParentCategoryId: strPtr("kaos")}))
+	assert.Equal(t, 0.5, categoryScore(target, &entity.RelatedCandidate{CategoryId: "kemeja", ParentCategoryId: strPtr("pakaian")}))
+	assert.Equal(t, 0.0, categoryScore(target, &entity.RelatedCandidate{CategoryId: "sepatu"}))
+}
+
+func TestBrandScore(t *testing.T) {
+	linked := &entity.RelatedCandidate{BrandId: strPtr("erigo"), Merk: "Erigo"}
+
+	assert.Equal(t, 1.0, brandScore(linked, &entity.RelatedCandidate{BrandId: strPtr("erigo")}))
+	assert.Equal(t, 0.0, brandScore(linked, &entity.RelatedCandidate{BrandId: strPtr("other"), Merk: "Erigo"}))
+	assert.Equal(t, 1.0, brandScore(linked, &entity.RelatedCandidate{Merk: "ERIGO Official Store"}))
+	assert.Equal(t, 0.0, brandScore(&entity.RelatedCandidate{}, &entity.RelatedCandidate{}))
+}
+
+func TestPriceAndTextScore(t *testing.T) {
+	assert.Equal(t, 1.0, priceScore(100, 100))
+	assert.Equal(t, 0.5, priceScore(50, 100))
+	assert.Equal(t, 0.5, priceScore(100, 50))
+	assert.Equal(t, 1.0, priceScore(0, 0))
+
+	// prices are compared in the default currency, not as stored
+	assert.Equal(t, 1.0, basePriceScore(idrPtr(15000000), idrPtr(15000000)))
+	assert.Equal(t, 0.0, basePriceScore(idrPtr(15000000), nil))
+
+	assert.Equal(t, 0.5, textScore(entity.NameTokens("Kaos Hitam"), entity.NameTokens("kaos-hitam polos jumbo, a")))
+	assert.Equal(t, 0.0, textScore(nil, entity.NameTokens("Kaos")))
+}
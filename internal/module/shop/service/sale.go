@@ -0,0 +1,36 @@
+package service
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"time"
+)
+
+// SetProductSale checks the sale period before storing it, a sale must end
+// after it starts and not be over already.
+func (s *shopService) SetProductSale(ctx context.Context, req *entity.SetProductSaleRequest) (*entity.ProductSaleResponse, error) {
+	if req.EndsAt != nil {
+		start := time.Now()
+		if req.StartsAt != nil && req.StartsAt.After(start) {
+			start = *req.StartsAt
+		}
+
+		if !req.EndsAt.After(start) {
+			return nil, errmsg.NewCustomErrors(400,
+				errmsg.WithMessage("Periode diskon tidak valid"),
+				errmsg.WithErrors("endsAt", "endsAt harus setelah startsAt dan waktu sekarang."),
+			)
+		}
+	}
+
+	return s.repo.SetProductSale(ctx, req)
+}
+
+func (s *shopService) DeleteProductSale(ctx context.Context, req *entity.DeleteProductSaleRequest) (*entity.ProductSaleResponse, error) {
+	return s.repo.DeleteProductSale(ctx, req)
+}
+
+func (s *shopService) GetPriceHistory(ctx context.Context, req *entity.GetPriceHistoryRequest) (*entity.GetPriceHistoryResponse, error) {
+	return s.repo.GetPriceHistory(ctx, req)
+}
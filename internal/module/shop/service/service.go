@@ -43,11 +43,36 @@ func (s *shopService) CreateProduct(ctx context.Context, req *entity.CreateProdu
 }
 
 func (s *shopService) GetProduct(ctx context.Context, req *entity.GetProductRequest) (*entity.GetProductResponse, error) {
-	return s.repo.GetProduct(ctx, req)
+	converter, err := s.newPriceConverter(ctx, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.repo.GetProduct(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range resp.ProductItem {
+		converter.product(&resp.ProductItem[i])
+	}
+
+	return resp, nil
 }
 
 func (s *shopService) GetProdctByid(ctx context.Context, req *entity.GetProductIdRequest) (*entity.GetProductIdResponse, error) {
-	return s.repo.GetProductByid(ctx, req)
+	converter, err := s.newPriceConverter(ctx, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.repo.GetProductByid(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	converter.productDetail(resp)
+
+	return resp, nil
 }
 
 func (s *shopService) UpdateProduct(ctx context.Context, req *entity.UpdateProductRequest) (*entity.UpdateProductResponse, error) {
@@ -73,3 +98,125 @@ func (s *shopService) GetCategoryId(ctx context.Context, req *entity.GetCategory
 func (s *shopService) DeletCategory(ctx context.Context, req *entity.DeleteCategoryRequest) error {
 	return s.repo.DeletCategory(ctx, req)
 }
+
+func (s *shopService) GetVariants(ctx context.Context, req *entity.GetVariantsRequest) (*entity.GetVariantsResponse, error) {
+	return s.repo.GetVariants(ctx, req)
+}
+
+func (s *shopService) CreateVariant(ctx context.Context, req *entity.CreateVariantRequest) (*entity.CreateVariantResponse, error) {
+	return s.repo.CreateVariant(ctx, req)
+}
+
+func (s *shopService) UpdateVariant(ctx context.Context, req *entity.UpdateVariantRequest) (*entity.UpdateVariantResponse, error) {
+	return s.repo.UpdateVariant(ctx, req)
+}
+
+func (s *shopService) DeleteVariant(ctx context.Context, req *entity.DeleteVariantRequest) error {
+	return s.repo.DeleteVariant(ctx, req)
+}
+
+func (s *shopService) GetProductImages(ctx context.Context, req *entity.GetProductImagesRequest) (*entity.GetProductImagesResponse, error) {
+	return s.repo.GetProductImages(ctx, req)
+}
+
+func (s *shopService) AddProductImages(ctx context.Context, req *entity.AddProductImagesRequest) (*entity.GetProductImagesResponse, error) {
+	return s.repo.AddProductImages(ctx, req)
+}
+
+func (s *shopService) ReorderProductImages(ctx context.Context, req *entity.ReorderProductImagesRequest) (*entity.GetProductImagesResponse, error) {
+	return s.repo.ReorderProductImages(ctx, req)
+}
+
+func (s *shopService) SetPrimaryProductImage(ctx context.Context, req *entity.SetPrimaryProductImageRequest) (*entity.GetProductImagesResponse, error) {
+	return s.repo.SetPrimaryProductImage(ctx, req)
+}
+
+func (s *shopService) DeleteProductImage(ctx context.Context, req *entity.DeleteProductImageRequest) error {
+	return s.repo.DeleteProductImage(ctx, req)
+}
+
+func (s *shopService) GetCategoryTree(ctx context.Context, req *entity.GetCategoryTreeRequest) (*entity.GetCategoryTreeResponse, error) {
+	return s.repo.GetCategoryTree(ctx, req)
+}
+
+func (s *shopService) MoveCategory(ctx context.Context, req *entity.MoveCategoryRequest) (*entity.MoveCategoryResponse, error) {
+	return s.repo.MoveCategory(ctx, req)
+}
+
+func (s *shopService) UpdateCategory(ctx context.Context, req *entity.UpdateCategoryRequest) (*entity.UpdateCategoryResponse, error) {
+	return s.repo.UpdateCategory(ctx, req)
+}
+
+func (s *shopService) GetReviews(ctx context.Context, req *entity.GetReviewsRequest) (*entity.GetReviewsResponse, error) {
+	return s.repo.GetReviews(ctx, req)
+}
+
+func (s *shopService) CreateReview(ctx context.Context, req *entity.CreateReviewRequest) (*entity.CreateReviewResponse, error) {
+	return s.repo.CreateReview(ctx, req)
+}
+
+func (s *shopService) UpdateReview(ctx context.Context, req *entity.UpdateReviewRequest) (*entity.UpdateReviewResponse, error) {
+	return s.repo.UpdateReview(ctx, req)
+}
+
+func (s *shopService) DeleteReview(ctx context.Context, req *entity.DeleteReviewRequest) error {
+	return s.repo.DeleteReview(ctx, req)
+}
+
+func (s *shopService) ReplyReview(ctx context.Context, req *entity.ReplyReviewRequest) (*entity.ReplyReviewResponse, error) {
+	return s.repo.ReplyReview(ctx, req)
+}
+
+func (s *shopService) UpdateProductStatus(ctx context.Context, req *entity.UpdateProductStatusRequest) (*entity.UpdateProductStatusResponse, error) {
+	return s.repo.UpdateProductStatus(ctx, req)
+}
+
+func (s *shopService) GetTrash(ctx context.Context, req *entity.GetTrashRequest) (*entity.GetTrashResponse, error) {
+	return s.repo.GetTrash(ctx, req)
+}
+
+func (s *shopService) RestoreTrash(ctx context.Context, req *entity.RestoreTrashRequest) (*entity.RestoreTrashResponse, error) {
+	return s.repo.RestoreTrash(ctx, req)
+}
+
+func (s *shopService) PurgeTrash(ctx context.Context, req *entity.PurgeTrashRequest) (*entity.PurgeTrashResponse, error) {
+	return s.repo.PurgeTrash(ctx, req)
+}
+
+func (s *shopService) GetProductRevisions(ctx context.Context, req *entity.GetProductRevisionsRequest) (*entity.GetProductRevisionsResponse, error) {
+	return s.repo.GetProductRevisions(ctx, req)
+}
+
+func (s *shopService) RollbackProduct(ctx context.Context, req *entity.RollbackProductRequest) (*entity.RollbackProductResponse, error) {
+	return s.repo.RollbackProduct(ctx, req)
+}
+
+func (s *shopService) GetShopBySlug(ctx context.Context, req *entity.GetShopBySlugRequest) (*entity.GetShopBySlugResponse, error) {
+	return s.repo.GetShopBySlug(ctx, req)
+}
+
+func (s *shopService) GetProductBySlug(ctx context.Context, req *entity.GetProductBySlugRequest) (*entity.GetProductBySlugResponse, error) {
+	converter, err := s.newPriceConverter(ctx, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.repo.GetProductBySlug(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.GetProductIdResponse != nil {
+		converter.productDetail(resp.GetProductIdResponse)
+	}
+
+	return resp, nil
+}
+
+func (s *shopService) GetTags(ctx context.Context, req *entity.GetTagsRequest) (*entity.GetTagsResponse, error) {
+	return s.repo.GetTags(ctx, req)
+}
+
+func (s *shopService) SetBundleComponents(ctx context.Context, req *entity.SetBundleComponentsRequest) (*entity.SetBundleComponentsResponse, error) {
+	return s.repo.SetBundleComponents(ctx, req)
+}
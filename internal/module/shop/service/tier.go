@@ -0,0 +1,70 @@
+package service
+
+import (
+	"codebase-app/internal/module/shop/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"sort"
+)
+
+// SetPriceTiers sorts the tiers by quantity and checks that buying more never
+// costs more per unit.
+func (s *shopService) SetPriceTiers(ctx context.Context, req *entity.SetPriceTiersRequest) (*entity.SetPriceTiersResponse, error) {
+	sort.Slice(req.Tiers, func(i, j int) bool {
+		return req.Tiers[i].MinQuantity < req.Tiers[j].MinQuantity
+	})
+
+	for i := 1; i < len(req.Tiers); i++ {
+		if req.Tiers[i].Price.Amount >= req.Tiers[i-1].Price.Amount {
+			return nil, errmsg.NewCustomErrors(400,
+				errmsg.WithMessage("Harga grosir tidak valid"),
+				errmsg.WithErrors("tiers", "harga harus semakin rendah untuk jumlah yang lebih banyak."),
+			)
+		}
+	}
+
+	return s.repo.SetPriceTiers(ctx, req)
+}
+
+func (s *shopService) GetPriceQuote(ctx context.Context, req *entity.GetPriceQuoteRequest) (*entity.GetPriceQuoteResponse, error) {
+	product, err := s.repo.GetPricedProduct(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return quotePrice(product, req.Quantity), nil
+}
+
+// quotePrice computes the price of buying quantity units. The cheapest tier
+// reached applies, unless the running sale is cheaper.
+func quotePrice(product *entity.PricedProduct, quantity int) *entity.GetPriceQuoteResponse {
+	var resp = &entity.GetPriceQuoteResponse{
+		ProductId: product.Id,
+		Quantity:  quantity,
+		UnitPrice: product.EffectivePrice,
+		Available: product.TotalStock >= quantity,
+	}
+
+	for i := range product.Tiers {
+		tier := &product.Tiers[i]
+		if tier.MinQuantity <= quantity && tier.Price.Amount < resp.UnitPrice.Amount {
+			resp.Tier = tier
+		}
+	}
+	if resp.Tier != nil {
+		resp.UnitPrice = resp.Tier.Price
+	}
+
+	// the next tier is the first one that would lower the unit price
+	for i := range product.Tiers {
+		tier := &product.Tiers[i]
+		if tier.MinQuantity > quantity && tier.Price.Amount < resp.UnitPrice.Amount {
+			resp.NextTier = tier
+			break
+		}
+	}
+
+	resp.Total = resp.UnitPrice.Mul(quantity)
+
+	return resp
+}
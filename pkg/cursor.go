@@ -0,0 +1,26 @@
+package pkg
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// EncodeCursor turns v into an opaque url-safe pagination cursor.
+func EncodeCursor(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor reads a cursor made by EncodeCursor into v.
+func DecodeCursor(cursor string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -138,29 +138,20 @@ func errorValidationHandler[T any](err error, payload *T) (int, map[string][]str
 			// message = fmt.Sprintf("%s must be a number.", fieldInMsg)
 			message = fmt.Sprintf("%s harus angka.", fieldInMsg)
 		case "eqfield":
-			eqField := err.Param()
-			eqFieldName := ""
-			eqFieldTag, _ := reflect.TypeOf(payload).Elem().FieldByName(eqField)
-			eqFieldJSONTag := eqFieldTag.Tag.Get("json")
-			eqFieldQueryTag := eqFieldTag.Tag.Get("query")
-			eqFieldFormTag := eqFieldTag.Tag.Get("form")
-			eqFieldParamsTag := eqFieldTag.Tag.Get("params")
-
-			if eqFieldJSONTag != "" {
-				eqFieldName = strings.ReplaceAll(eqFieldJSONTag, "_", " ")
-			}
-			if eqFieldQueryTag != "" {
-				eqFieldName = strings.ReplaceAll(eqFieldQueryTag, "_", " ")
-			}
-			if eqFieldFormTag != "" {
-				eqFieldName = strings.ReplaceAll(eqFieldFormTag, "_", " ")
-			}
-			if eqFieldParamsTag != "" {
-				eqFieldName = strings.ReplaceAll(eqFieldParamsTag, "_", " ")
-			}
+			eqFieldName := fieldNameInMsg(payload, err.Param())
 
 			// message = fmt.Sprintf("%s must be equal to %s.", fieldInMsg, eqFieldName)
 			message = fmt.Sprintf("%s harus sama dengan %s.", fieldInMsg, eqFieldName)
+		case "nefield":
+			neFieldName := fieldNameInMsg(payload, err.Param())
+
+			// message = fmt.Sprintf("%s must not be equal to %s.", fieldInMsg, neFieldName)
+			message = fmt.Sprintf("%s tidak boleh sama dengan %s.", fieldInMsg, neFieldName)
+		case "gtefield":
+			gteFieldName := fieldNameInMsg(payload, err.Param())
+
+			// message = fmt.Sprintf("%s must be greater than or equal to %s.", fieldInMsg, gteFieldName)
+			message = fmt.Sprintf("%s harus lebih dari atau sama dengan %s.", fieldInMsg, gteFieldName)
 		case "oneof":
 			// message = fmt.Sprintf("%s must be one of %s.", fieldInMsg, err.Param())
 			// message = fmt.Sprintf("%s harus salah satu dari %s.", fieldInMsg, err.Param())
@@ -174,6 +165,24 @@ func errorValidationHandler[T any](err error, payload *T) (int, map[string][]str
 		case "unique_in_slice":
 			// message = fmt.Sprintf("%s elements must be unique.", fieldInMsg)
 			message = fmt.Sprintf("elemen %s harus unik.", fieldInMsg)
+		case "required_if":
+			// message = fmt.Sprintf("%s is required.", fieldInMsg)
+			message = fmt.Sprintf("%s harus diisi.", fieldInMsg)
+		case "required_unless":
+			// message = fmt.Sprintf("%s is required.", fieldInMsg)
+			message = fmt.Sprintf("%s harus diisi.", fieldInMsg)
+		case "required_with":
+			// message = fmt.Sprintf("%s is required.", fieldInMsg)
+			message = fmt.Sprintf("%s harus diisi.", fieldInMsg)
+		case "unique":
+			// message = fmt.Sprintf("%s elements must be unique.", fieldInMsg)
+			message = fmt.Sprintf("elemen %s harus unik.", fieldInMsg)
+		case "excluded_unless":
+			// message = fmt.Sprintf("%s must be empty.", fieldInMsg)
+			message = fmt.Sprintf("%s tidak boleh diisi.", fieldInMsg)
+		case "iso4217":
+			// message = fmt.Sprintf("%s must be an ISO 4217 currency code.", fieldInMsg)
+			message = fmt.Sprintf("%s harus kode mata uang ISO 4217.", fieldInMsg)
 		}
 
 		errorMessages[field] = append(errorMessages[field], message)
@@ -181,3 +190,30 @@ func errorValidationHandler[T any](err error, payload *T) (int, map[string][]str
 
 	return code, errorMessages
 }
+
+// fieldNameInMsg returns the readable name of a payload field, taken from its
+// json, query, form or params tag.
+func fieldNameInMsg[T any](payload *T, field string) string {
+	var name string
+
+	fieldTag, _ := reflect.TypeOf(payload).Elem().FieldByName(field)
+	jsonTag := fieldTag.Tag.Get("json")
+	queryTag := fieldTag.Tag.Get("query")
+	formTag := fieldTag.Tag.Get("form")
+	paramsTag := fieldTag.Tag.Get("params")
+
+	if jsonTag != "" {
+		name = strings.ReplaceAll(jsonTag, "_", " ")
+	}
+	if queryTag != "" {
+		name = strings.ReplaceAll(queryTag, "_", " ")
+	}
+	if formTag != "" {
+		name = strings.ReplaceAll(formTag, "_", " ")
+	}
+	if paramsTag != "" {
+		name = strings.ReplaceAll(paramsTag, "_", " ")
+	}
+
+	return name
+}
@@ -33,3 +33,25 @@ func Errors[T any](err error, payloads ...*T) (code int, errors any) {
 
 	return code, errors
 }
+
+// FieldErrors works like Errors but always returns the field errors as a map,
+// the message of a custom error without field errors is reported under "error".
+// Unknown errors are not exposed.
+func FieldErrors[T any](err error, payloads ...*T) map[string][]string {
+	_, errs := Errors(err, payloads...)
+
+	switch e := errs.(type) {
+	case map[string][]string:
+		if len(e) == 0 {
+			e["error"] = []string{"Permintaan Anda gagal diproses"}
+		}
+		return e
+	case *CustomError:
+		if len(e.Errors) == 0 {
+			return map[string][]string{"error": {e.Msg}}
+		}
+		return e.Errors
+	}
+
+	return map[string][]string{"error": {"Permintaan Anda gagal diproses"}}
+}
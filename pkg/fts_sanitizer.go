@@ -17,7 +17,7 @@ func SanitizeKeyword(keyword string) string {
 }
 
 func FormatKeywords(keyword string) string {
-	keywords := strings.Split(keyword, " ")
+	keywords := strings.Fields(keyword)
 	for i, keyword := range keywords {
 		keyword = SanitizeKeyword(keyword)
 		keywords[i] = keyword + ":*"
@@ -0,0 +1,33 @@
+package pkg
+
+import "strings"
+
+// slugMaxLength leaves room for the numeric suffix that keeps a slug unique.
+const slugMaxLength = 100
+
+// Slugify turns a name into a lower case, dash separated slug made of ASCII
+// letters and digits, e.g. "Kopi Susu (250 ml)" becomes "kopi-susu-250-ml".
+// It returns an empty string when the name has no usable character.
+func Slugify(s string) string {
+	var sb strings.Builder
+
+	dash := false
+	for _, c := range strings.ToLower(s) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(c)
+			dash = false
+		default:
+			dash = true
+		}
+
+		if sb.Len() >= slugMaxLength {
+			break
+		}
+	}
+
+	return strings.TrimSuffix(sb.String()[:min(sb.Len(), slugMaxLength)], "-")
+}
@@ -0,0 +1,235 @@
+package pkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The largest sheet Excel supports, references past it are refused.
+const (
+	xlsxMaxRows    = 1048576
+	xlsxMaxColumns = 16384
+)
+
+// ErrTooManyRows is returned when a file has more rows than the caller allows.
+var ErrTooManyRows = errors.New("spreadsheet has too many rows")
+
+// ReadSpreadsheet returns the rows of a CSV or XLSX file, the format is picked
+// from the file extension. Only the first sheet of a workbook is read. Reading
+// stops with ErrTooManyRows past maxRows rows, empty ones included.
+func ReadSpreadsheet(filename string, data []byte, maxRows int) ([][]string, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return ReadCSV(data, maxRows)
+	case ".xlsx":
+		return ReadXLSX(data, maxRows)
+	default:
+		return nil, errors.New("unsupported spreadsheet format")
+	}
+}
+
+// ReadCSV reads a comma separated file, rows may have a different number of
+// fields. Blank lines are kept as empty rows so the index of a row always
+// matches its line in the file. More than maxRows rows is ErrTooManyRows.
+func ReadCSV(data []byte, maxRows int) ([][]string, error) {
+	// strip the byte order mark some spreadsheet applications prepend
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var (
+		r       = csv.NewReader(bytes.NewReader(data))
+		results = make([][]string, 0)
+	)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+		if line > maxRows {
+			return nil, ErrTooManyRows
+		}
+		for len(results) < line-1 {
+			results = append(results, nil)
+		}
+
+		results = append(results, record)
+	}
+
+	return results, nil
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+
+	var sb strings.Builder
+	for _, r := range t.Runs {
+		sb.WriteString(r.Text)
+	}
+
+	return sb.String()
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Ref   int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSX reads the cell values of the first worksheet of an XLSX workbook.
+// Formulas are read from their cached value and no number formatting is applied.
+// More than maxRows rows is ErrTooManyRows.
+func ReadXLSX(data []byte, maxRows int) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		files   = make(map[string]*zip.File, len(zr.File))
+		sheets  = make([]string, 0)
+		strs    xlsxSharedStrings
+		sheet   xlsxSheet
+		results = make([][]string, 0)
+	)
+
+	for _, f := range zr.File {
+		files[f.Name] = f
+		if strings.HasPrefix(f.Name, "xl/worksheets/sheet") && strings.HasSuffix(f.Name, ".xml") {
+			sheets = append(sheets, f.Name)
+		}
+	}
+
+	if len(sheets) == 0 {
+		return nil, errors.New("workbook has no worksheet")
+	}
+
+	// sheet1.xml is the first sheet for every common writer, fall back to the
+	// lowest numbered one otherwise
+	sheetName := "xl/worksheets/sheet1.xml"
+	if _, ok := files[sheetName]; !ok {
+		sort.Strings(sheets)
+		sheetName = sheets[0]
+	}
+
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(f, &strs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := decodeZipXML(files[sheetName], &sheet); err != nil {
+		return nil, err
+	}
+
+	for _, row := range sheet.Rows {
+		if row.Ref < 0 || row.Ref > xlsxMaxRows {
+			return nil, errors.New("invalid row reference")
+		}
+		if max(row.Ref, len(results)+1) > maxRows {
+			return nil, ErrTooManyRows
+		}
+
+		// rows without any value are omitted from the sheet as well
+		for len(results) < row.Ref-1 {
+			results = append(results, nil)
+		}
+
+		values := make([]string, 0, len(row.Cells))
+
+		for _, cell := range row.Cells {
+			col := len(values)
+			if cell.Ref != "" {
+				col = xlsxColumnIndex(cell.Ref)
+				if col < 0 {
+					return nil, errors.New("invalid cell reference")
+				}
+			}
+
+			// empty cells are omitted from the sheet, pad the gaps
+			for len(values) < col {
+				values = append(values, "")
+			}
+
+			var value string
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(strs.Items) {
+					return nil, errors.New("invalid shared string reference")
+				}
+				value = strs.Items[idx].String()
+			case "inlineStr":
+				value = cell.Inline.String()
+			default:
+				value = cell.Value
+			}
+
+			values = append(values, value)
+		}
+
+		results = append(results, values)
+	}
+
+	return results, nil
+}
+
+func decodeZipXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return xml.NewDecoder(io.LimitReader(rc, 64<<20)).Decode(v)
+}
+
+// xlsxColumnIndex turns the column letters of a cell reference ("AB12") into a
+// zero based column index. It is -1 without letters or past the last column.
+func xlsxColumnIndex(ref string) int {
+	col := 0
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = col*26 + int(c-'A'+1)
+		if col > xlsxMaxColumns {
+			return -1
+		}
+	}
+
+	return col - 1
+}
@@ -0,0 +1,161 @@
+package pkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		maxRows int
+		want    [][]string
+		wantErr error
+	}{
+		{
+			name:    "rows of different length",
+			data:    "name,price\nKaos,15000,extra\n",
+			maxRows: 10,
+			want:    [][]string{{"name", "price"}, {"Kaos", "15000", "extra"}},
+		},
+		{
+			name:    "byte order mark and blank line",
+			data:    "\xef\xbb\xbfname\n\nKaos\n",
+			maxRows: 10,
+			want:    [][]string{{"name"}, nil, {"Kaos"}},
+		},
+		{
+			name:    "too many rows",
+			data:    "name\nKaos\nTopi\n",
+			maxRows: 2,
+			wantErr: ErrTooManyRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadCSV([]byte(tt.data), tt.maxRows)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// testXLSX builds a workbook holding the given sheet data and shared strings.
+func testXLSX(t *testing.T, sheetData, sharedStrings string) []byte {
+	var (
+		buf = new(bytes.Buffer)
+		zw  = zip.NewWriter(buf)
+	)
+
+	files := map[string]string{
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` + sheetData + `</sheetData></worksheet>`,
+		"xl/sharedStrings.xml":     `<sst>` + sharedStrings + `</sst>`,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func TestReadXLSX(t *testing.T) {
+	tests := []struct {
+		name      string
+		sheetData string
+		maxRows   int
+		want      [][]string
+		wantErr   error
+		anyErr    bool
+	}{
+		{
+			name: "shared and inline strings",
+			sheetData: `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="inlineStr"><is><t>price</t></is></c></row>` +
+				`<row r="2"><c r="A2" t="s"><v>1</v></c><c r="B2"><v>15000</v></c></row>`,
+			maxRows: 10,
+			want:    [][]string{{"name", "price"}, {"Kaos", "15000"}},
+		},
+		{
+			name:      "row and cell gaps are padded",
+			sheetData: `<row r="1"><c r="A1"><v>1</v></c></row><row r="3"><c r="C3"><v>3</v></c></row>`,
+			maxRows:   10,
+			want:      [][]string{{"1"}, nil, {"", "", "3"}},
+		},
+		{
+			name:      "cells without reference follow each other",
+			sheetData: `<row><c><v>1</v></c><c><v>2</v></c></row>`,
+			maxRows:   10,
+			want:      [][]string{{"1", "2"}},
+		},
+		{
+			name:      "row past the sheet limit",
+			sheetData: `<row r="2000000000"><c r="A2000000000"><v>1</v></c></row>`,
+			maxRows:   xlsxMaxRows + 1,
+			anyErr:    true,
+		},
+		{
+			name:      "column past the sheet limit",
+			sheetData: `<row r="1"><c r="ZZZZZZZ1"><v>1</v></c></row>`,
+			maxRows:   10,
+			anyErr:    true,
+		},
+		{
+			name:      "row past the allowed rows",
+			sheetData: `<row r="1"><c r="A1"><v>1</v></c></row><row r="50"><c r="A50"><v>1</v></c></row>`,
+			maxRows:   10,
+			wantErr:   ErrTooManyRows,
+		},
+		{
+			name:      "invalid shared string",
+			sheetData: `<row r="1"><c r="A1" t="s"><v>9</v></c></row>`,
+			maxRows:   10,
+			anyErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := testXLSX(t, tt.sheetData, `<si><t>name</t></si><si><r><t>Ka</t></r><r><t>os</t></r></si>`)
+
+			got, err := ReadXLSX(data, tt.maxRows)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.anyErr:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestXLSXColumnIndex(t *testing.T) {
+	tests := map[string]int{
+		"A1":       0,
+		"Z9":       25,
+		"AA1":      26,
+		"AB12":     27,
+		"XFD1":     xlsxMaxColumns - 1,
+		"XFE1":     -1,
+		"ZZZZZZZ1": -1,
+		"12":       -1,
+	}
+
+	for ref, want := range tests {
+		assert.Equal(t, want, xlsxColumnIndex(ref), ref)
+	}
+}
@@ -5,6 +5,11 @@ type Meta struct {
 	Paginate  int `json:"paginate"`
 	TotalData int `json:"total_data"`
 	TotalPage int `json:"total_page"`
+
+	// Cursor and NextCursor are only filled when the listing runs in cursor mode,
+	// an empty NextCursor means there are no more rows.
+	Cursor     string `json:"cursor,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 func (r *Meta) CountTotalPage(page, paginate, totalData int) {
@@ -27,3 +32,10 @@ func (r *Meta) CountTotalPage(page, paginate, totalData int) {
 		r.TotalPage = 1
 	}
 }
+
+// SetCursor fills the meta of a cursor paginated listing.
+func (r *Meta) SetCursor(cursor, nextCursor string, paginate int) {
+	r.Cursor = cursor
+	r.NextCursor = nextCursor
+	r.Paginate = paginate
+}
@@ -0,0 +1,177 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of the amounts stored in the price columns.
+const DefaultCurrency = "IDR"
+
+// moneyScale is the number of minor units in a unit, the price columns are
+// DECIMAL(10, 2).
+const moneyScale = 100
+
+// Money is an amount in minor units (hundredths) of a currency, it avoids the
+// rounding of float64 prices. It is stored as a DECIMAL and encoded in JSON as
+// a plain number of units, e.g. 15000.5, so existing clients keep working.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// NewMoney returns an amount given in minor units.
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney reads a decimal amount of units in the default currency, e.g.
+// "15000" or "15000.50". More than two decimals are refused.
+func ParseMoney(s string) (Money, error) {
+	var (
+		value    = strings.TrimSpace(s)
+		negative = strings.HasPrefix(value, "-")
+	)
+
+	if negative || strings.HasPrefix(value, "+") {
+		value = value[1:]
+	}
+
+	units, fraction, _ := strings.Cut(value, ".")
+	fraction = strings.TrimRight(fraction, "0")
+	if units == "" || len(fraction) > 2 || !isDigits(units) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("invalid money amount %q", s)
+	}
+
+	amount, err := strconv.ParseInt(units, 10, 64)
+	if err != nil || amount > math.MaxInt64/moneyScale {
+		return Money{}, fmt.Errorf("invalid money amount %q", s)
+	}
+	amount *= moneyScale
+
+	if fraction != "" {
+		cents, _ := strconv.ParseInt((fraction + "0")[:2], 10, 64)
+		amount += cents
+	}
+
+	if negative {
+		amount = -amount
+	}
+
+	return Money{Amount: amount, Currency: DefaultCurrency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the amount as a decimal number of units without trailing
+// zeros, e.g. "15000" or "15000.5".
+func (m Money) String() string {
+	var (
+		amount = m.Amount
+		sign   string
+	)
+
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	units, cents := amount/moneyScale, amount%moneyScale
+	if cents == 0 {
+		return sign + strconv.FormatInt(units, 10)
+	}
+
+	return sign + strconv.FormatInt(units, 10) + "." + strings.TrimRight(fmt.Sprintf("%02d", cents), "0")
+}
+
+// Float64 is the amount in units, only meant for ratios and display.
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / moneyScale
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Mul returns the amount multiplied by a quantity.
+func (m Money) Mul(quantity int) Money {
+	return Money{Amount: m.Amount * int64(quantity), Currency: m.Currency}
+}
+
+// Convert returns the amount in another currency, rate being the value of one
+// unit of m's currency in that one. It is rounded to the minor unit.
+func (m Money) Convert(rate float64, currency string) Money {
+	return Money{Amount: int64(math.Round(float64(m.Amount) * rate)), Currency: currency}
+}
+
+// Scan implements the sql.Scanner interface, the currency is the default one.
+func (m *Money) Scan(src any) error {
+	var (
+		parsed Money
+		err    error
+	)
+
+	switch v := src.(type) {
+	case []byte:
+		parsed, err = ParseMoney(string(v))
+	case string:
+		parsed, err = ParseMoney(v)
+	case int64:
+		parsed = Money{Amount: v * moneyScale, Currency: DefaultCurrency}
+	case float64:
+		parsed = Money{Amount: int64(math.Round(v * moneyScale)), Currency: DefaultCurrency}
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a number or a string holding one.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var s string
+
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	} else {
+		s = string(b)
+	}
+
+	return m.UnmarshalText([]byte(s))
+}
+
+// UnmarshalText reads the amount of form and query values.
+func (m *Money) UnmarshalText(b []byte) error {
+	parsed, err := ParseMoney(string(b))
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	for s, amount := range map[string]int64{
+		"15000":    1500000,
+		"15000.5":  1500050,
+		"15000.05": 1500005,
+		"0.10":     10,
+		"-2.5":     -250,
+		"12.500":   1250,
+	} {
+		m, err := ParseMoney(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, amount, m.Amount, s)
+		assert.Equal(t, DefaultCurrency, m.Currency, s)
+	}
+
+	for _, s := range []string{"", "abc", "1.005", "1e3", ".5", "1,5"} {
+		_, err := ParseMoney(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestMoneyEncoding(t *testing.T) {
+	assert.Equal(t, "15000", NewMoney(1500000, DefaultCurrency).String())
+	assert.Equal(t, "15000.5", NewMoney(1500050, DefaultCurrency).String())
+	assert.Equal(t, "-0.05", NewMoney(-5, DefaultCurrency).String())
+
+	var m Money
+	assert.NoError(t, m.Scan([]byte("19999.99")))
+	assert.Equal(t, int64(1999999), m.Amount)
+
+	var req struct {
+		Price    Money  `json:"price"`
+		MinPrice *Money `json:"minPrice"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"price":"120.25","minPrice":null}`), &req))
+	assert.Equal(t, int64(12025), req.Price.Amount)
+	assert.Nil(t, req.MinPrice)
+
+	b, err := json.Marshal(req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"price":120.25,"minPrice":null}`, string(b))
+}
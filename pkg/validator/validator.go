@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"codebase-app/pkg/types"
 	"reflect"
 	"strings"
 
@@ -52,6 +53,14 @@ func NewValidator() *Validator {
 		return name
 	})
 
+	// money is validated on its amount in minor units
+	v.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		if m, ok := field.Interface().(types.Money); ok {
+			return m.Amount
+		}
+		return nil
+	}, types.Money{})
+
 	// en_translations.RegisterDefaultTranslations(v, trans)
 	if err := v.RegisterValidation("email_blacklist", isEmailBlacklist); err != nil {
 		log.Fatal().Err(err).Msg("Error while registering email_blacklist validator")